@@ -0,0 +1,13 @@
+package textwire
+
+import "github.com/textwire/textwire/v4/pkg/linker"
+
+// Audit returns warnings about components and layouts that are never used,
+// @reserve directives that are never filled by @insert and @slot directives
+// that never receive content. Warnings don't prevent templates from being
+// rendered and are never returned as errors.
+func (t *Template) Audit() []linker.Warning {
+	t.linker.RLock()
+	defer t.linker.RUnlock()
+	return t.linker.Audit()
+}
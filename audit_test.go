@@ -0,0 +1,97 @@
+package textwire
+
+import (
+	"testing"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/linker"
+)
+
+func TestTemplateAudit(t *testing.T) {
+	type warning struct {
+		kind linker.WarningKind
+		name string
+		line uint
+		msg  string
+	}
+
+	cases := []struct {
+		dir    string
+		expect []warning
+	}{
+		{dir: "use-with-comp-inside", expect: []warning{}},
+		{
+			dir: "audit",
+			expect: []warning{
+				{
+					linker.WarnUnusedComponent,
+					"components/button",
+					1,
+					"component 'components/button' is not used by any @component directive",
+				},
+				{
+					linker.WarnUnusedReserve,
+					"layouts/main",
+					2,
+					"@reserve('footer') in layout 'layouts/main' is never filled by @insert",
+				},
+				{
+					linker.WarnUnusedLayout,
+					"layouts/old",
+					1,
+					"layout 'layouts/old' is not used by any @use directive",
+				},
+			},
+		},
+		{
+			dir: "slots-optional",
+			expect: []warning{
+				{
+					linker.WarnUnusedSlot,
+					"comp",
+					1,
+					"default @slot in component 'comp' never receives content",
+				},
+				{
+					linker.WarnUnusedSlot,
+					"comp",
+					2,
+					"@slot('h2') in component 'comp' never receives content from @pass",
+				},
+				{
+					linker.WarnUnusedSlot,
+					"comp",
+					3,
+					"@slot('h3') in component 'comp' never receives content from @pass",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.dir, func(t *testing.T) {
+			tpl, failure := NewTemplate(&config.Config{
+				TemplateDir: "testdata/good/before/" + tc.dir,
+			})
+			if failure != nil {
+				t.Fatalf("unexpected error: %s", failure)
+			}
+
+			warnings := tpl.Audit()
+			if len(warnings) != len(tc.expect) {
+				t.Fatalf("expected %d warnings, got %v", len(tc.expect), warnings)
+			}
+
+			for i, w := range warnings {
+				expect := tc.expect[i]
+				if w.Kind != expect.kind || w.Name != expect.name || w.Message != expect.msg {
+					t.Fatalf("wrong warning %d. Expect: %+v, got: %+v", i, expect, w)
+				}
+
+				if w.Pos.Line() != expect.line {
+					t.Fatalf("expected warning %d on line %d, got %d", i, expect.line, w.Pos.Line())
+				}
+			}
+		})
+	}
+}
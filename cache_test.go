@@ -0,0 +1,208 @@
+package textwire
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/i18n"
+)
+
+// fakeCache records TTLs of stored content.
+type fakeCache struct {
+	*config.MemoryCache
+	ttls []time.Duration
+}
+
+func (c *fakeCache) Set(key, content string, ttl time.Duration) {
+	c.ttls = append(c.ttls, ttl)
+	c.MemoryCache.Set(key, content, ttl)
+}
+
+func TestCacheDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/index.tw": {
+			Data: []byte("@cache('nav')<b>{{ name }}</b>@end|" +
+				"@cache('user-' + name, '5m'){{ name }}@end|" +
+				"@cache(1, 60)@end"),
+		},
+	}
+
+	cache := &fakeCache{MemoryCache: config.NewMemoryCache(10)}
+	watcher := &fakeWatcher{}
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fsys,
+		Cache:       cache,
+		FileWatcher: true,
+		Watcher:     watcher,
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{Cache: config.NewMemoryCache(1000)})
+
+	cases := []struct {
+		id     uint
+		name   string
+		expect string
+	}{
+		{1, "Anna", "<b>Anna</b>|Anna|"},
+		{2, "Serhii", "<b>Anna</b>|Serhii|"},
+		{3, "Anna", "<b>Anna</b>|Anna|"},
+	}
+
+	for _, tc := range cases {
+		out, failure := tpl.String("index", map[string]any{"name": tc.name})
+		if failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		if out != tc.expect {
+			t.Fatalf("Case: %d. wrong output. Expect %q, got %q", tc.id, tc.expect, out)
+		}
+	}
+
+	if cache.Len() != 3 {
+		t.Fatalf("expected 3 cached entries, got %d", cache.Len())
+	}
+
+	if cache.ttls[0] != 0 || cache.ttls[1] != 5*time.Minute {
+		t.Fatalf("wrong TTLs %v", cache.ttls)
+	}
+
+	fsys["templates/index.tw"].Data = []byte("@cache('nav')<i>{{ name }}</i>@end")
+	watcher.onChange([]string{"templates/index.tw"})
+
+	out, failure := tpl.String("index", map[string]any{"name": "Serhii"})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	if out != "<i>Serhii</i>" {
+		t.Fatalf("expected cache to be invalidated after relinking, got %q", out)
+	}
+}
+
+func TestCacheDirLocale(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/index.tw": {Data: []byte("@cache('nav'){{ __('home') }}@end")},
+	}
+
+	catalog := i18n.NewCatalog("en")
+	enErr := catalog.Add("en", map[string]any{"home": "Home"})
+	ukErr := catalog.Add("uk", map[string]any{"home": "Головна"})
+	if enErr != nil || ukErr != nil {
+		t.Fatalf("unexpected errors: %v, %v", enErr, ukErr)
+	}
+
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fsys,
+		Translator:  catalog,
+		Cache:       config.NewMemoryCache(10),
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{Cache: config.NewMemoryCache(1000)})
+
+	cases := []struct {
+		id     uint
+		locale string
+		expect string
+	}{
+		{1, "en", "Home"},
+		{2, "uk", "Головна"},
+		{3, "en", "Home"},
+	}
+
+	for _, tc := range cases {
+		ctx := i18n.WithLocale(context.Background(), tc.locale)
+		out, failure := tpl.StringContext(ctx, "index", nil)
+		if failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		if out != tc.expect {
+			t.Fatalf("Case: %d. wrong output. Expect %q, got %q", tc.id, tc.expect, out)
+		}
+	}
+}
+
+func TestCacheDirOutputMode(t *testing.T) {
+	page := []byte("@component('comps/name', {name})")
+	fsys := fstest.MapFS{
+		"templates/email.txt.tw":  {Data: page},
+		"templates/page.tw":       {Data: page},
+		"templates/comps/name.tw": {Data: []byte("@cache('name'){{ name }}@end")},
+	}
+
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fsys,
+		Cache:       config.NewMemoryCache(10),
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{Cache: config.NewMemoryCache(1000)})
+
+	cases := []struct {
+		id     uint
+		name   string
+		expect string
+	}{
+		{1, "page", "&lt;b&gt;"},
+		{2, "email.txt", "<b>"},
+		{3, "page", "&lt;b&gt;"},
+	}
+
+	for _, tc := range cases {
+		out, failure := tpl.String(tc.name, map[string]any{"name": "<b>"})
+		if failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		if out != tc.expect {
+			t.Fatalf("Case: %d. wrong output. Expect %q, got %q", tc.id, tc.expect, out)
+		}
+	}
+}
+
+func TestCacheDirErrors(t *testing.T) {
+	cases := []struct {
+		id   uint
+		inp  string
+		code fail.Code
+	}{
+		{1, "@cache(['nav'])x@end", fail.CodeCacheKeyType},
+		{2, "@cache('nav', true)x@end", fail.CodeCacheTTLType},
+		{3, "@cache('nav', '5 minutes')x@end", fail.CodeCacheTTLType},
+	}
+
+	for _, tc := range cases {
+		tpl, failure := NewTemplate(&config.Config{
+			TemplateDir: "templates",
+			TemplateFS: fstest.MapFS{
+				"templates/index.tw": {Data: []byte(tc.inp)},
+			},
+		})
+		if failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		_, failure = tpl.String("index", nil)
+		if failure == nil || failure.Code() != tc.code {
+			t.Fatalf("Case: %d. expected error with code %q, got %v", tc.id, tc.code, failure)
+		}
+	}
+
+	Configure(&config.Config{})
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/textwire/textwire/v4"
+	"github.com/textwire/textwire/v4/config"
+)
+
+// runAudit parses templates and prints audit warnings, one per line.
+func runAudit(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("audit", flag.ContinueOnError)
+	dir := flags.String("dir", "templates", "directory with Textwire templates")
+	ext := flags.String("ext", ".tw", "extension of Textwire templates")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	tpl, failure := textwire.NewTemplate(&config.Config{TemplateDir: *dir, TemplateExt: *ext})
+	if failure != nil {
+		return failure
+	}
+
+	for _, w := range tpl.Audit() {
+		if _, err := fmt.Fprintln(out, w.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/textwire/textwire/v4"
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/linker"
+)
+
+// runGraph parses templates and writes their dependency graph.
+func runGraph(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	dir := flags.String("dir", "templates", "directory with Textwire templates")
+	ext := flags.String("ext", ".tw", "extension of Textwire templates")
+	format := flags.String("format", "dot", "output format, dot or json")
+	output := flags.String("o", "", "output file, defaults to stdout")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	tpl, failure := textwire.NewTemplate(&config.Config{TemplateDir: *dir, TemplateExt: *ext})
+	if failure != nil {
+		return failure
+	}
+
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch *format {
+	case "dot":
+		return writeDOT(out, tpl.Graph())
+	case "json":
+		return writeJSON(out, tpl.Graph())
+	}
+
+	return fmt.Errorf("unknown format %q, use dot or json", *format)
+}
+
+// writeDOT writes dependencies in Graphviz DOT format. Layout
+// dependencies are drawn with dashed edges.
+func writeDOT(out io.Writer, deps []linker.Dependency) error {
+	var b strings.Builder
+	b.WriteString("digraph textwire {\n")
+
+	for _, dep := range deps {
+		attrs := "label=" + strconv.Quote(string(dep.Kind))
+		if dep.Kind == linker.DepLayout {
+			attrs += ", style=dashed"
+		}
+
+		from, to := strconv.Quote(dep.From), strconv.Quote(dep.To)
+		fmt.Fprintf(&b, "    %s -> %s [%s];\n", from, to, attrs)
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// writeJSON writes dependencies as an indented JSON array.
+func writeJSON(out io.Writer, deps []linker.Dependency) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "    ")
+	return enc.Encode(deps)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunGraph(t *testing.T) {
+	dir := "../../testdata/good/before/use-with-comp-inside"
+
+	cases := []struct {
+		id     uint
+		format string
+		expect string
+	}{
+		{
+			1,
+			"dot",
+			`digraph textwire {
+    "index" -> "layouts/layout-with-component" [label="layout", style=dashed];
+    "layouts/layout-with-component" -> "components/navbar" [label="component"];
+}
+`,
+		},
+		{
+			2,
+			"json",
+			`[
+    {
+        "from": "index",
+        "to": "layouts/layout-with-component",
+        "kind": "layout",
+        "inserts": [
+            "content",
+            "description",
+            "title"
+        ]
+    },
+    {
+        "from": "layouts/layout-with-component",
+        "to": "components/navbar",
+        "kind": "component"
+    }
+]
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		out := &strings.Builder{}
+		if err := run([]string{"graph", "-dir", dir, "-format", tc.format}, out); err != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, err)
+		}
+
+		if out.String() != tc.expect {
+			t.Fatalf("Case: %d. wrong output. Expect:\n%s\ngot:\n%s", tc.id, tc.expect, out)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage: textwire <command> [flags]
+
+Commands:
+  audit    print warnings about unused components, layouts, reserves and slots
+  graph    write dependency graph of templates in DOT or JSON format
+  profile  evaluate a template and report its slowest loops, calls and components
+
+Run "textwire <command> -h" to see flags of the command.
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("command is required")
+	}
+
+	switch args[0] {
+	case "audit":
+		return runAudit(args[1:], out)
+	case "graph":
+		return runGraph(args[1:], out)
+	case "profile":
+		return runProfile(args[1:], out)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(out, usage)
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unknown command %q", args[0])
+}
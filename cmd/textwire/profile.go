@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/textwire/textwire/v4"
+	"github.com/textwire/textwire/v4/config"
+)
+
+// runProfile evaluates the template and writes where evaluation spends time.
+func runProfile(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("profile", flag.ContinueOnError)
+	dir := flags.String("dir", "templates", "directory with Textwire templates")
+	ext := flags.String("ext", ".tw", "extension of Textwire templates")
+	dataPath := flags.String("data", "", "JSON file with data for the template")
+	format := flags.String("format", "text", "output format, text or pprof")
+	top := flags.Int("n", 10, "number of entries in each section of text output, 0 for all")
+	output := flags.String("o", "", "output file, defaults to stdout")
+
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: textwire profile [flags] <template>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("template name is required")
+	}
+
+	data := map[string]any{}
+	if *dataPath != "" {
+		content, err := os.ReadFile(*dataPath)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(content, &data); err != nil {
+			return fmt.Errorf("cannot parse %s: %w", *dataPath, err)
+		}
+	}
+
+	tpl, failure := textwire.NewTemplate(&config.Config{TemplateDir: *dir, TemplateExt: *ext})
+	if failure != nil {
+		return failure
+	}
+
+	prof, failure := tpl.Profile(flags.Arg(0), data)
+	if failure != nil {
+		return failure
+	}
+
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch *format {
+	case "text":
+		return prof.WriteText(out, *top)
+	case "pprof":
+		return prof.WritePprof(out)
+	}
+
+	return fmt.Errorf("unknown format %q, use text or pprof", *format)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache stores rendered content of @cache directives. Implement it to
+// share cached content between instances of your app, like with Redis.
+// Keys already contain the template path and change when templates are
+// changed, so implementations don't need to invalidate anything.
+// It must be safe for concurrent use.
+type Cache interface {
+	// Get returns the content stored with the key
+	// or false when it's missing or expired.
+	Get(key string) (string, bool)
+
+	// Set stores the content with the key. Content must expire after ttl,
+	// a ttl of 0 means that the content doesn't expire.
+	Set(key, content string, ttl time.Duration)
+}
+
+type memoryEntry struct {
+	key       string
+	content   string
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-memory Cache that evicts least recently used
+// entries when it's full. It's the default Cache.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// NewMemoryCache returns an in-memory LRU cache that holds up to size entries.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    max(size, 1),
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (c *MemoryCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return "", false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return entry.content, true
+}
+
+func (c *MemoryCache) Set(key, content string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryEntry{key: key, content: content}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Len returns the number of stored entries, including expired ones.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
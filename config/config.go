@@ -2,9 +2,13 @@ package config
 
 import (
 	"io/fs"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/i18n"
 )
 
 // Config holds the configuration settings for Textwire template engine.
@@ -48,13 +52,16 @@ type Config struct {
 	// FileWatcher watches all of your template files for changes and
 	// automatically re-parses them when they are modified. This is intended
 	// for development use only and should not be enabled in production due to
-	// performance implications. It doesn't work with TemplateFS configuration
-	// enabled! Disable it to use file refresh functionality.
+	// performance implications. It works with TemplateDir and with any
+	// TemplateFS that returns modification times from fs.Stat.
 	// Default: false
 	FileWatcher bool
 
 	// WatcherInterval specifies how often Textwire checks for changes in
-	// template files when FileWatcher is enabled. The higher the interval,
+	// template files when FileWatcher is enabled. On Linux, file system
+	// events are used for TemplateDir instead and this interval only applies
+	// when they are not available, when TemplateFS is used or when Textwire
+	// falls back to polling for any other reason. The higher the interval,
 	// the less frequently Textwire checks for file changes, which can reduce
 	// CPU usage but may delay updates. Values less than 1 second will be
 	// treated as the default (1 second). Adjust this value based on your
@@ -62,6 +69,97 @@ type Config struct {
 	// Default: time.Second (1 second)
 	WatcherInterval time.Duration
 
+	// Watcher replaces the built-in file watcher with your own implementation
+	// when FileWatcher is enabled. By default, Textwire uses file system
+	// events for TemplateDir on Linux and polls TemplateFS every
+	// WatcherInterval otherwise.
+	// Default: nil (built-in watcher)
+	Watcher Watcher
+
+	// LiveReloadPath is the URL path where you mount the handler returned
+	// by Template.LiveReloadHandler(). When it's set together with
+	// DebugMode and FileWatcher, a small script is injected into HTML pages
+	// rendered by Template.Response(). The script reloads the page when
+	// templates change or shows an overlay when they contain errors.
+	// Default: "" (disabled)
+	LiveReloadPath string
+
+	// Undefined chooses what happens when a template uses a variable that
+	// is not defined. UndefinedError fails the evaluation, UndefinedEmpty
+	// renders an empty string and UndefinedPlaceholder renders
+	// UndefinedPlaceholder instead. Member and index access on undefined or
+	// nil variables, like `user.name`, is replaced the same way. The
+	// defined() and hasValue() functions work the same way in every mode.
+	// Default: UndefinedError
+	Undefined UndefinedMode
+
+	// UndefinedPlaceholder is rendered in place of undefined variables when
+	// Undefined is UndefinedPlaceholder. Every "%s" in it is replaced with
+	// the variable name or the object key.
+	// Default: "[undefined %s]"
+	UndefinedPlaceholder string
+
+	// StrictKeys treats missing object keys, like `user.nme` or
+	// `user['nme']`, the same way as undefined variables. Without it,
+	// missing keys silently evaluate to nil. Combine it with UndefinedError
+	// in CI to catch typos in templates.
+	// Default: false
+	StrictKeys bool
+
+	// OnUndefined is called with the would-be error every time an undefined
+	// variable or a missing object key is replaced because Undefined is not
+	// UndefinedError. The error contains the file path, position and the
+	// template call stack. Use it to log warnings.
+	// Default: nil
+	OnUndefined func(failure *fail.Error)
+
+	// Logger receives structured logs from Textwire: file watcher events,
+	// loaded templates, errors that are rendered as error pages and warnings
+	// about undefined variables when Undefined is not UndefinedError. Errors
+	// are logged with "template", "path", "line" and "col" attributes.
+	// Use slog.New(slog.DiscardHandler) to disable logging.
+	// Default: slog.Default()
+	Logger *slog.Logger
+
+	// Output sets how values printed with "{{ }}" are escaped. Use
+	// OutputText for plain-text emails or Markdown, OutputJSON for JSON
+	// and OutputShell for shell scripts. Templates can choose the mode
+	// with an extension before TemplateExt, like "email.txt.tw" for
+	// OutputText, "data.json.tw" for OutputJSON or "run.sh.tw" for
+	// OutputShell. In all modes except OutputHTML, @dump prints plain
+	// text. Strings returned by raw() are never escaped.
+	// Default: OutputHTML
+	Output OutputMode
+
+	// Translator translates messages for the `__('messages.welcome',
+	// {name: user.name})` global function. Use i18n.NewCatalog and
+	// Catalog.Load to load messages from JSON or YAML files, or implement
+	// the i18n.Translator interface yourself. Missing messages render
+	// their keys and are logged as warnings.
+	// Default: nil
+	Translator i18n.Translator
+
+	// Locale is the locale used by __() and by locale-aware functions,
+	// like format(), currency(), formatDate() and relativeTime(), when
+	// the render call doesn't set one. Set the locale for a render call
+	// with i18n.WithLocale and Template.StringContext. Numbers and dates
+	// are formatted in English for locales without formatting rules.
+	// Default: ""
+	Locale string
+
+	// TrimDirectiveLines removes lines that contain nothing but directives,
+	// like "@if(x)" or "@end", from the output together with their
+	// indentation and line break. Whitespace inside directive blocks is
+	// kept as it is instead of being trimmed. Use "{{- x -}}" and "~"
+	// around directives, like "~@if(x)~", to trim whitespace elsewhere.
+	// Default: false
+	TrimDirectiveLines bool
+
+	// Cache stores rendered content of @cache directives. Content cached
+	// before templates were changed by the file watcher is never used.
+	// Default: NewMemoryCache(1000)
+	Cache Cache
+
 	// usesFS is a flag to determine if user uses TemplateFS or not.
 	usesFS bool
 }
@@ -74,6 +172,9 @@ func New() *Config {
 		DebugMode:       false,
 		GlobalData:      map[string]any{},
 		WatcherInterval: time.Second,
+
+		UndefinedPlaceholder: "[undefined %s]",
+		Cache:                NewMemoryCache(1000),
 	}
 }
 
@@ -82,6 +183,15 @@ func (c *Config) UsesFS() bool {
 	return c.usesFS
 }
 
+// Log returns Logger or slog.Default() when Logger is not set.
+func (c *Config) Log() *slog.Logger {
+	if c.Logger == nil {
+		return slog.Default()
+	}
+
+	return c.Logger
+}
+
 func (c *Config) Configure(opt *Config) {
 	if opt == nil {
 		return
@@ -113,6 +223,26 @@ func (c *Config) Configure(opt *Config) {
 		c.GlobalData = opt.GlobalData
 	}
 
+	if opt.UndefinedPlaceholder != "" {
+		c.UndefinedPlaceholder = opt.UndefinedPlaceholder
+	}
+
+	if opt.Cache != nil {
+		c.Cache = opt.Cache
+	}
+
+	c.Logger = opt.Logger
+	c.TrimDirectiveLines = opt.TrimDirectiveLines
+	c.Output = opt.Output
+	c.Translator = opt.Translator
+	c.Locale = opt.Locale
+	c.Undefined = opt.Undefined
+	c.StrictKeys = opt.StrictKeys
+	c.OnUndefined = opt.OnUndefined
+
+	c.Watcher = opt.Watcher
+	c.LiveReloadPath = opt.LiveReloadPath
+
 	c.FileWatcher = opt.FileWatcher
 	c.DebugMode = opt.DebugMode
 	c.usesFS = opt.TemplateFS != nil
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// OutputMode controls how values printed with "{{ }}" are escaped
+// and how @dump formats them.
+type OutputMode uint8
+
+const (
+	// OutputHTML escapes special HTML characters.
+	OutputHTML OutputMode = iota
+
+	// OutputText prints values as they are and makes @dump
+	// produce plain text.
+	OutputText
+
+	// OutputJSON escapes values to be placed inside of JSON strings
+	// and makes @dump produce plain text.
+	OutputJSON
+
+	// OutputShell wraps strings in single quotes for POSIX shells
+	// and makes @dump produce plain text.
+	OutputShell
+)
+
+// outputExts maps extensions that go before TemplateExt, like ".txt" in
+// "email.txt.tw", to output modes.
+var outputExts = map[string]OutputMode{
+	".html": OutputHTML,
+	".htm":  OutputHTML,
+	".xml":  OutputHTML,
+	".svg":  OutputHTML,
+	".txt":  OutputText,
+	".text": OutputText,
+	".md":   OutputText,
+	".yaml": OutputText,
+	".yml":  OutputText,
+	".csv":  OutputText,
+	".json": OutputJSON,
+	".sh":   OutputShell,
+}
+
+// String returns the name of the mode, like "html" or "text".
+func (m OutputMode) String() string {
+	switch m {
+	case OutputText:
+		return "text"
+	case OutputJSON:
+		return "json"
+	case OutputShell:
+		return "shell"
+	default:
+		return "html"
+	}
+}
+
+// OutputFor returns the output mode of the template file with the given
+// path. The extension before TemplateExt, like ".txt" in "email.txt.tw",
+// chooses the mode. Files without a known extension use Output.
+func (c *Config) OutputFor(path string) OutputMode {
+	name := strings.TrimSuffix(path, c.TemplateExt)
+	if mode, ok := outputExts[strings.ToLower(filepath.Ext(name))]; ok {
+		return mode
+	}
+
+	return c.Output
+}
@@ -0,0 +1,30 @@
+package config
+
+// UndefinedMode controls how templates handle variables that are not
+// defined and, with StrictKeys, object keys that don't exist.
+type UndefinedMode uint8
+
+const (
+	// UndefinedError fails the evaluation with fail.ErrVariableIsUndefined.
+	UndefinedError UndefinedMode = iota
+
+	// UndefinedEmpty evaluates undefined variables to nil,
+	// which renders as an empty string.
+	UndefinedEmpty
+
+	// UndefinedPlaceholder evaluates undefined variables to
+	// Config.UndefinedPlaceholder string.
+	UndefinedPlaceholder
+)
+
+// String returns the name of the mode, like "error" or "empty".
+func (m UndefinedMode) String() string {
+	switch m {
+	case UndefinedEmpty:
+		return "empty"
+	case UndefinedPlaceholder:
+		return "placeholder"
+	default:
+		return "error"
+	}
+}
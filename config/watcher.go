@@ -0,0 +1,15 @@
+package config
+
+// Watcher notifies Textwire about changes in template files when
+// FileWatcher is enabled. Implement it to watch your own template sources,
+// like a remote storage or a custom fs.FS that can't be polled efficiently.
+type Watcher interface {
+	// Watch starts watching in the background and must not block. It calls
+	// onChange with slash-separated paths of changed, created or deleted
+	// files and directories relative to the root of TemplateFS.
+	// It's safe to call onChange from multiple goroutines.
+	Watch(onChange func(paths []string)) error
+
+	// Close stops watching and releases resources.
+	Close() error
+}
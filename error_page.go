@@ -0,0 +1,170 @@
+package textwire
+
+import (
+	_ "embed"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/position"
+)
+
+//go:embed embed/default-error-page.tw
+var defaultErrPage string
+
+// excerptContext is how many lines are shown before and after
+// the failing lines in the source excerpt of the error page.
+const excerptContext = 3
+
+// errorPage returns HTML that's displayed when an error occurs while
+// rendering template. Source excerpt, variables in scope and template
+// stack are only added in DebugMode.
+func (t *Template) errorPage(failure *fail.Error) (string, *fail.Error) {
+	data := map[string]any{
+		"path":      failure.Filepath(),
+		"line":      failure.Pos().Line(),
+		"col":       failure.Pos().Col(),
+		"message":   failure.Message(),
+		"excerpt":   []map[string]any{},
+		"vars":      []map[string]any{},
+		"stack":     []map[string]any{},
+		"debugMode": userConf.DebugMode,
+	}
+
+	if userConf.DebugMode {
+		data["excerpt"] = t.sourceExcerpt(failure.Filepath(), failure.Pos())
+		data["vars"] = varsData(failure.Vars())
+		data["stack"] = stackData(failure.Stack())
+	}
+
+	out, err := evaluateString(defaultErrPage, data, config.OutputHTML)
+	if err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
+
+// sourceExcerpt returns lines around the failing position. Each failing
+// line is followed by a marker line that underlines the failing span.
+// It returns no lines when the file is not one of the parsed templates.
+func (t *Template) sourceExcerpt(absPath string, pos *position.Pos) []map[string]any {
+	content, ok := t.readSource(absPath)
+	if !ok {
+		return []map[string]any{}
+	}
+
+	lines := strings.Split(content, "\n")
+	endLine := max(pos.EndLine, pos.StartLine)
+	if int(pos.StartLine) >= len(lines) {
+		return []map[string]any{}
+	}
+
+	first := max(int(pos.StartLine)-excerptContext, 0)
+	last := min(int(endLine)+excerptContext, len(lines)-1)
+	excerpt := make([]map[string]any, 0, last-first+1)
+
+	for i := first; i <= last; i++ {
+		text := strings.TrimRight(lines[i], "\r")
+		failing := uint(i) >= pos.StartLine && uint(i) <= endLine
+
+		excerpt = append(excerpt, map[string]any{
+			"number":  i + 1,
+			"text":    text,
+			"failing": failing,
+			"marker":  "",
+		})
+
+		if !failing {
+			continue
+		}
+
+		from, to := 0, len(text)-1
+		if uint(i) == pos.StartLine {
+			from = int(pos.StartCol)
+		}
+
+		if uint(i) == endLine && pos.EndLine >= pos.StartLine {
+			to = int(pos.EndCol)
+		}
+
+		excerpt[len(excerpt)-1]["marker"] = underline(text, from, to)
+	}
+
+	return excerpt
+}
+
+// underline returns a marker line with ^ characters under bytes from
+// the start to the end index of the text, both inclusive. Tabs before the
+// start are kept to align the marker with the text.
+func underline(text string, start, end int) string {
+	start = min(start, len(text))
+	end = max(min(end, len(text)-1), start)
+
+	var out strings.Builder
+	for i := range start {
+		if text[i] == '\t' {
+			out.WriteByte('\t')
+			continue
+		}
+		out.WriteByte(' ')
+	}
+
+	out.WriteString(strings.Repeat("^", end-start+1))
+
+	return out.String()
+}
+
+// readSource returns the content of the parsed template file
+// with the given absolute path.
+func (t *Template) readSource(absPath string) (string, bool) {
+	if absPath == "" {
+		return "", false
+	}
+
+	t.linker.RLock()
+	defer t.linker.RUnlock()
+
+	for _, prog := range t.linker.Programs {
+		if prog.AbsPath == absPath {
+			return prog.Source, true
+		}
+	}
+
+	return "", false
+}
+
+// varsData converts variables in scope into data for the error page
+// sorted by name.
+func varsData(vars map[string]string) []map[string]any {
+	names := slices.Sorted(maps.Keys(vars))
+
+	data := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		data = append(data, map[string]any{"name": name, "value": vars[name]})
+	}
+
+	return data
+}
+
+// stackData converts the template call stack into data for the error page.
+func stackData(stack []fail.Frame) []map[string]any {
+	frames := make([]map[string]any, 0, len(stack))
+	for _, frame := range stack {
+		location := frame.Filepath
+		if frame.Pos != nil && frame.Kind != fail.FramePage {
+			location = fmt.Sprintf("%s:%d", frame.Filepath, frame.Pos.Line())
+		}
+
+		frames = append(frames, map[string]any{
+			"kind":     string(frame.Kind),
+			"name":     frame.Name,
+			"location": location,
+		})
+	}
+
+	return frames
+}
@@ -0,0 +1,45 @@
+package textwire
+
+import (
+	"github.com/textwire/textwire/v4/pkg/file"
+	"github.com/textwire/textwire/v4/pkg/linker"
+)
+
+// Graph returns the dependency graph of all templates, layouts and
+// components. Each dependency describes @use or @component relation
+// between two templates with passed inserts or slots.
+func (t *Template) Graph() []linker.Dependency {
+	t.linker.RLock()
+	defer t.linker.RUnlock()
+	return t.linker.Graph()
+}
+
+// Dependencies returns layouts and components that the template with
+// the given name uses directly. Names are the same as in String(),
+// like "home" or "components/book".
+func (t *Template) Dependencies(name string) []linker.Dependency {
+	name = file.ReplacePathAlias(name, file.PathAliasViews)
+	return t.filterGraph(func(dep linker.Dependency) bool {
+		return dep.From == name
+	})
+}
+
+// Dependents returns templates that directly use the layout or component
+// with the given name, like "layouts/main" or "components/book".
+func (t *Template) Dependents(name string) []linker.Dependency {
+	name = file.ReplacePathAlias(name, file.PathAliasViews)
+	return t.filterGraph(func(dep linker.Dependency) bool {
+		return dep.To == name
+	})
+}
+
+func (t *Template) filterGraph(keep func(dep linker.Dependency) bool) []linker.Dependency {
+	deps := make([]linker.Dependency, 0, 4)
+	for _, dep := range t.Graph() {
+		if keep(dep) {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps
+}
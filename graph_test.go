@@ -0,0 +1,98 @@
+package textwire
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/linker"
+)
+
+func TestTemplateDependencies(t *testing.T) {
+	cases := []struct {
+		dir        string
+		name       string
+		deps       []linker.Dependency
+		dependents []linker.Dependency
+	}{
+		{
+			dir:  "use-with-comp-inside",
+			name: "index",
+			deps: []linker.Dependency{
+				{
+					From:    "index",
+					To:      "layouts/layout-with-component",
+					Kind:    linker.DepLayout,
+					Inserts: []string{"content", "description", "title"},
+				},
+			},
+			dependents: []linker.Dependency{},
+		},
+		{
+			dir:  "use-with-comp-inside",
+			name: "layouts/layout-with-component",
+			deps: []linker.Dependency{
+				{
+					From: "layouts/layout-with-component",
+					To:   "components/navbar",
+					Kind: linker.DepComponent,
+				},
+			},
+			dependents: []linker.Dependency{
+				{
+					From:    "index",
+					To:      "layouts/layout-with-component",
+					Kind:    linker.DepLayout,
+					Inserts: []string{"content", "description", "title"},
+				},
+			},
+		},
+		{
+			dir:  "comp-and-passes",
+			name: "index",
+			deps: []linker.Dependency{
+				{
+					From:  "index",
+					To:    "user",
+					Kind:  linker.DepComponent,
+					Slots: []string{"", "age", "empty", "name"},
+				},
+				{From: "index", To: "书", Kind: linker.DepComponent, Slots: []string{"", "1"}},
+			},
+			dependents: []linker.Dependency{},
+		},
+		{
+			dir:  "comp-and-passes",
+			name: "user",
+			deps: []linker.Dependency{{From: "user", To: "small", Kind: linker.DepComponent}},
+			dependents: []linker.Dependency{
+				{
+					From:  "index",
+					To:    "user",
+					Kind:  linker.DepComponent,
+					Slots: []string{"", "age", "empty", "name"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.dir+"/"+tc.name, func(t *testing.T) {
+			tpl, failure := NewTemplate(&config.Config{
+				TemplateDir: "testdata/good/before/" + tc.dir,
+			})
+			if failure != nil {
+				t.Fatalf("unexpected error: %s", failure)
+			}
+
+			if deps := tpl.Dependencies(tc.name); !reflect.DeepEqual(deps, tc.deps) {
+				t.Fatalf("wrong dependencies. Expect:\n%#v\ngot:\n%#v", tc.deps, deps)
+			}
+
+			dependents := tpl.Dependents(tc.name)
+			if !reflect.DeepEqual(dependents, tc.dependents) {
+				t.Fatalf("wrong dependents. Expect:\n%#v\ngot:\n%#v", tc.dependents, dependents)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package textwire
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/hooks"
+)
+
+// recordHooks records events in the order they were received.
+type recordHooks struct {
+	events []string
+}
+
+func (h *recordHooks) OnRenderStart(ctx context.Context, r hooks.Render) context.Context {
+	h.events = append(h.events, "render start "+r.Template)
+	return ctx
+}
+
+func (h *recordHooks) OnRenderEnd(_ context.Context, r hooks.Render) {
+	h.events = append(h.events, fmt.Sprintf("render end %s %d", r.Template, r.Size))
+}
+
+func (h *recordHooks) OnComponentStart(ctx context.Context, c hooks.Component) context.Context {
+	h.events = append(h.events, "component start "+c.Name)
+	return ctx
+}
+
+func (h *recordHooks) OnComponentEnd(_ context.Context, c hooks.Component) {
+	h.events = append(h.events, fmt.Sprintf("component end %s %d", c.Name, c.Size))
+}
+
+func (h *recordHooks) OnFunctionCall(_ context.Context, call hooks.FunctionCall) {
+	h.events = append(h.events, fmt.Sprintf("call %s.%s", call.Receiver, call.Name))
+}
+
+// fakeTracer records started spans with the name of the parent span.
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+type fakeSpan struct {
+	name   string
+	parent string
+	attrs  []hooks.Attr
+	ended  bool
+}
+
+type fakeSpanKey struct{}
+
+func (t *fakeTracer) Start(
+	ctx context.Context,
+	name string,
+	_ time.Time,
+	attrs ...hooks.Attr,
+) (context.Context, hooks.Span) {
+	span := &fakeSpan{name: name, attrs: attrs}
+	if parent, ok := ctx.Value(fakeSpanKey{}).(*fakeSpan); ok {
+		span.parent = parent.name
+	}
+
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, fakeSpanKey{}, span), span
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...hooks.Attr) { s.attrs = append(s.attrs, attrs...) }
+func (s *fakeSpan) RecordError(error)                 {}
+func (s *fakeSpan) End(time.Time)                     { s.ended = true }
+
+func newHooksTemplate(t *testing.T) *Template {
+	t.Helper()
+
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS: fstest.MapFS{
+			"templates/index.tw":           {Data: []byte("@component('~card')@end{{ 'a'.upper() }}")},
+			"templates/components/card.tw": {Data: []byte("<b>{{ 'hi'.len() }}</b>")},
+		},
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	return tpl
+}
+
+func TestTemplateHooks(t *testing.T) {
+	tpl := newHooksTemplate(t)
+	defer Configure(&config.Config{})
+
+	record := &recordHooks{}
+	tpl.SetHooks(hooks.Multi(hooks.Nop{}, record))
+
+	if _, failure := tpl.String("index", nil); failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	expect := []string{
+		"render start index",
+		"component start components/card",
+		"call string.len",
+		"component end components/card 8",
+		"call string.upper",
+		"render end index 9",
+	}
+
+	if !slices.Equal(record.events, expect) {
+		t.Fatalf("wrong events. Expect:\n%q\ngot:\n%q", expect, record.events)
+	}
+}
+
+func TestTracingHooks(t *testing.T) {
+	tpl := newHooksTemplate(t)
+	defer Configure(&config.Config{})
+
+	tracer := &fakeTracer{}
+	tpl.SetHooks(hooks.Tracing(tracer))
+
+	if _, failure := tpl.String("index", nil); failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	expect := [][2]string{
+		{hooks.SpanRender, ""},
+		{hooks.SpanComponent, hooks.SpanRender},
+		{hooks.SpanFunction, hooks.SpanComponent},
+		{hooks.SpanFunction, hooks.SpanRender},
+	}
+
+	if len(tracer.spans) != len(expect) {
+		t.Fatalf("expected %d spans, got %d", len(expect), len(tracer.spans))
+	}
+
+	for i, span := range tracer.spans {
+		if span.name != expect[i][0] || span.parent != expect[i][1] {
+			t.Fatalf("span %d must be %q with parent %q, got %q with parent %q",
+				i, expect[i][0], expect[i][1], span.name, span.parent)
+		}
+
+		if !span.ended {
+			t.Fatalf("span %d %q was not ended", i, span.name)
+		}
+	}
+
+	size := tracer.spans[0].attrs[len(tracer.spans[0].attrs)-1]
+	if size != (hooks.Attr{Key: hooks.AttrSize, Value: 9}) {
+		t.Fatalf("expected render span to have size 9, got %v", size)
+	}
+}
@@ -0,0 +1,84 @@
+package textwire
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"testing/fstest"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/i18n"
+)
+
+func TestTranslate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/index.tw": {
+			Data: []byte("{{ __('messages.welcome', {name: name}) }}|" +
+				"{{ __('cart.items', {count: count}) }}|{{ __('missing') }}"),
+		},
+	}
+
+	catalog := i18n.NewCatalog("en")
+	enErr := catalog.Add("en", map[string]any{
+		"messages": map[string]any{"welcome": "Hello, {name}!"},
+		"cart": map[string]any{"items": map[string]any{
+			"one":   "{count} item",
+			"other": "{count} items",
+		}},
+	})
+	ukErr := catalog.Add("uk", map[string]any{
+		"messages": map[string]any{"welcome": "Привіт, {name}!"},
+		"cart": map[string]any{"items": map[string]any{
+			"one":   "{count} товар",
+			"few":   "{count} товари",
+			"many":  "{count} товарів",
+			"other": "{count} товару",
+		}},
+	})
+	if enErr != nil || ukErr != nil {
+		t.Fatalf("unexpected errors: %v, %v", enErr, ukErr)
+	}
+
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fsys,
+		Translator:  catalog,
+		Locale:      "en",
+		Logger:      slog.New(slog.DiscardHandler),
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{})
+
+	cases := []struct {
+		id     uint
+		locale string
+		count  int
+		expect string
+	}{
+		{1, "", 1, "Hello, &lt;b&gt;!|1 item|missing"},
+		{2, "en", 2, "Hello, &lt;b&gt;!|2 items|missing"},
+		{3, "uk", 3, "Привіт, &lt;b&gt;!|3 товари|missing"},
+		{4, "uk", 5, "Привіт, &lt;b&gt;!|5 товарів|missing"},
+		{5, "de", 1, "Hello, &lt;b&gt;!|1 item|missing"},
+	}
+
+	for _, tc := range cases {
+		ctx := context.Background()
+		if tc.locale != "" {
+			ctx = i18n.WithLocale(ctx, tc.locale)
+		}
+
+		data := map[string]any{"name": "<b>", "count": tc.count}
+		out, failure := tpl.StringContext(ctx, "index", data)
+		if failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		if out != tc.expect {
+			t.Fatalf("Case: %d. wrong output. Expect %q, got %q", tc.id, tc.expect, out)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package textwire
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+
+	"github.com/textwire/textwire/v4/pkg/fail"
 )
 
 const (
@@ -12,8 +16,14 @@ const (
 	colorCyan   = "\033[36m"
 )
 
+// WatcherLogger prints colored messages of the file watcher.
+//
+// Deprecated: The file watcher logs to config.Config.Logger now.
 type WatcherLogger struct{}
 
+// NewWatcherLogger returns a new WatcherLogger.
+//
+// Deprecated: Use config.Config.Logger instead.
 func NewWatcherLogger() *WatcherLogger {
 	return &WatcherLogger{}
 }
@@ -44,3 +54,9 @@ func (l *WatcherLogger) Fatal(text string) {
 	l.Error(text)
 	os.Exit(1)
 }
+
+// logFailure logs the failure to config.Logger with its template name,
+// file path and position.
+func logFailure(level slog.Level, msg string, failure *fail.Error) {
+	userConf.Log().LogAttrs(context.Background(), level, msg, failure.LogAttrs()...)
+}
@@ -0,0 +1,232 @@
+package textwire
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/textwire/textwire/v4/config"
+)
+
+func TestOutputModes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/page.tw":       {Data: []byte("<p>{{ name }}</p>")},
+		"templates/email.txt.tw":  {Data: []byte("Hi {{ name }}, {{ tags }}")},
+		"templates/data.json.tw":  {Data: []byte(`{"name": "{{ name }}"}`)},
+		"templates/run.sh.tw":     {Data: []byte("echo {{ name }} {{ 5 }}")},
+		"templates/raw.sh.tw":     {Data: []byte("echo {{ name.raw() }}")},
+		"templates/dump.txt.tw":   {Data: []byte("@dump(name, tags, {b: 1, a: nil})")},
+		"templates/dump-html.tw":  {Data: []byte("@dump(1)")},
+		"templates/notes.text.tw": {Data: []byte("{{ name }}")},
+	}
+
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fsys,
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{})
+
+	data := map[string]any{
+		"name": `<O'Neil "Bob">`,
+		"tags": []string{"a&b", "c"},
+	}
+
+	cases := []struct {
+		id     uint
+		name   string
+		expect string
+	}{
+		{1, "page", "<p>&lt;O&#39;Neil &#34;Bob&#34;&gt;</p>"},
+		{2, "email.txt", `Hi <O'Neil "Bob">, a&b, c`},
+		{3, "data.json", `{"name": "\u003cO'Neil \"Bob\"\u003e"}`},
+		{4, "run.sh", `echo '<O'\''Neil "Bob">' 5`},
+		{5, "raw.sh", `echo <O'Neil "Bob">`},
+		{6, "dump.txt", "\"<O'Neil \\\"Bob\\\">\"\n" +
+			"array:2 [\n  \"a&b\",\n  \"c\",\n]\n" +
+			"object:2 {\n  \"a\": nil,\n  \"b\": 1,\n}\n"},
+		{7, "notes.text", `<O'Neil "Bob">`},
+	}
+
+	for _, tc := range cases {
+		out, failure := tpl.String(tc.name, data)
+		if failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		if out != tc.expect {
+			t.Fatalf("Case: %d. wrong output. Expect %q, got %q", tc.id, tc.expect, out)
+		}
+	}
+
+	out, failure := tpl.String("dump-html", nil)
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	if out == "1\n" {
+		t.Fatalf("expected HTML dump in HTML mode, got %q", out)
+	}
+}
+
+func TestOutputConfig(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/index.tw":      {Data: []byte("{{ name }}")},
+		"templates/index.html.tw": {Data: []byte("{{ name }}")},
+	}
+
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fsys,
+		Output:      config.OutputText,
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{})
+
+	cases := []struct {
+		id     uint
+		name   string
+		expect string
+	}{
+		{1, "index", "<b>"},
+		{2, "index.html", "&lt;b&gt;"},
+	}
+
+	for _, tc := range cases {
+		out, failure := tpl.String(tc.name, map[string]any{"name": "<b>"})
+		if failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		if out != tc.expect {
+			t.Fatalf("Case: %d. wrong output. Expect %q, got %q", tc.id, tc.expect, out)
+		}
+	}
+}
+
+func TestOutputInheritedFromPage(t *testing.T) {
+	page := []byte("@use('layouts/main')@insert('body')@component('comps/name', {name})@end@end")
+	fsys := fstest.MapFS{
+		"templates/email.txt.tw":    {Data: page},
+		"templates/page.tw":         {Data: page},
+		"templates/layouts/main.tw": {Data: []byte("{{ '<i>' }}|@reserve('body')")},
+		"templates/comps/name.tw":   {Data: []byte("{{ name }}")},
+	}
+
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fsys,
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{})
+
+	cases := []struct {
+		id     uint
+		name   string
+		expect string
+	}{
+		{1, "email.txt", "<i>|<b>"},
+		{2, "page", "&lt;i&gt;|&lt;b&gt;"},
+	}
+
+	for _, tc := range cases {
+		out, failure := tpl.String(tc.name, map[string]any{"name": "<b>"})
+		if failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		if out != tc.expect {
+			t.Fatalf("Case: %d. wrong output. Expect %q, got %q", tc.id, tc.expect, out)
+		}
+	}
+}
+
+func TestOutputEvaluateString(t *testing.T) {
+	Configure(&config.Config{Output: config.OutputText})
+	defer Configure(&config.Config{})
+
+	out, failure := EvaluateString("{{ name }}", map[string]any{"name": "<b>"})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	if out != "<b>" {
+		t.Fatalf("expected unescaped output, got %q", out)
+	}
+}
+
+func TestOutputLiveReload(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/page.tw":      {Data: []byte("<p>{{ name }}</p>")},
+		"templates/data.json.tw": {Data: []byte(`{"name": "{{ name }}"}`)},
+		"templates/run.sh.tw":    {Data: []byte("echo {{ name }}")},
+	}
+
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir:    "templates",
+		TemplateFS:     fsys,
+		DebugMode:      true,
+		FileWatcher:    true,
+		Watcher:        &fakeWatcher{},
+		LiveReloadPath: "/__reload",
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{})
+
+	cases := []struct {
+		id     uint
+		name   string
+		script bool
+	}{
+		{1, "page", true},
+		{2, "data.json", false},
+		{3, "run.sh", false},
+	}
+
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		if failure := tpl.Response(rec, tc.name, map[string]any{"name": "Anna"}); failure != nil {
+			t.Fatalf("Case: %d. unexpected error: %s", tc.id, failure)
+		}
+
+		if script := strings.Contains(rec.Body.String(), "<script>"); script != tc.script {
+			t.Fatalf("Case: %d. expected script %t, got:\n%s", tc.id, tc.script, rec.Body)
+		}
+	}
+}
+
+func TestOutputErrorPage(t *testing.T) {
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fstest.MapFS{"templates/index.tw": {Data: []byte("{{ missing }}")}},
+		Output:      config.OutputText,
+		DebugMode:   true,
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{})
+
+	rec := httptest.NewRecorder()
+	if failure := tpl.Response(rec, "index", nil); failure == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	if body := rec.Body.String(); !strings.Contains(body, "variable &#39;missing&#39;") {
+		t.Fatalf("expected error page to be escaped as HTML, got:\n%s", body)
+	}
+}
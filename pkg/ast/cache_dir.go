@@ -0,0 +1,56 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/textwire/textwire/v4/pkg/token"
+)
+
+type CacheDir struct {
+	BaseNode
+	Key   Expression // Cache key, any expression that evaluates to a string or a number
+	TTL   Expression // Optional time to live, nil when it's not provided
+	Block *Block
+
+	// Generation changes every time programs are linked, so that content
+	// cached before templates were changed is never used.
+	Generation uint64
+}
+
+func NewCacheDir(tok token.Token) *CacheDir {
+	return &CacheDir{
+		BaseNode: NewBaseNode(tok),
+	}
+}
+
+func (*CacheDir) chunkNode() {}
+
+func (cd *CacheDir) String() string {
+	var out strings.Builder
+	out.Grow(30)
+
+	out.WriteString("@cache(")
+	out.WriteString(cd.Key.String())
+
+	if cd.TTL != nil {
+		out.WriteString(", ")
+		out.WriteString(cd.TTL.String())
+	}
+
+	out.WriteString(")")
+
+	if cd.Block != nil {
+		out.WriteString(cd.Block.String())
+	}
+
+	out.WriteString("@end")
+
+	return out.String()
+}
+
+func (cd *CacheDir) AllChunks() []Chunk {
+	if cd.Block == nil {
+		return []Chunk{}
+	}
+	return cd.Block.AllChunks()
+}
@@ -0,0 +1,47 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/textwire/textwire/v4/pkg/token"
+)
+
+type CaseDir struct {
+	BaseNode
+	Values []Expression
+	Block  *Block // @case()<Block>@end
+}
+
+func NewCaseDir(tok token.Token) *CaseDir {
+	return &CaseDir{
+		BaseNode: NewBaseNode(tok),
+	}
+}
+
+func (*CaseDir) chunkNode() {}
+
+func (cd *CaseDir) String() string {
+	var out strings.Builder
+
+	values := make([]string, len(cd.Values))
+	for i, val := range cd.Values {
+		values[i] = val.String()
+	}
+
+	fmt.Fprintf(&out, "@case(%s)\n", strings.Join(values, ", "))
+
+	if cd.Block != nil {
+		out.WriteString(cd.Block.String())
+	}
+
+	return out.String()
+}
+
+func (cd *CaseDir) AllChunks() []Chunk {
+	if cd.Block == nil {
+		return []Chunk{}
+	}
+
+	return cd.Block.AllChunks()
+}
@@ -15,15 +15,25 @@ type argRules struct {
 }
 
 const (
-	defined    GlobalFuncName = "defined"
-	hasValue   GlobalFuncName = "hasValue"
-	formatDate GlobalFuncName = "formatDate"
+	defined      GlobalFuncName = "defined"
+	hasValue     GlobalFuncName = "hasValue"
+	formatDate   GlobalFuncName = "formatDate"
+	relativeTime GlobalFuncName = "relativeTime"
+	translate    GlobalFuncName = "__"
+	minimum      GlobalFuncName = "min"
+	maximum      GlobalFuncName = "max"
+	numRange     GlobalFuncName = "range"
 )
 
 var GlobalFunctions = map[GlobalFuncName]argRules{
-	defined:    {Min: 1, Max: 999},
-	hasValue:   {Min: 1, Max: 999},
-	formatDate: {Min: 2, Max: 2},
+	defined:      {Min: 1, Max: 999},
+	hasValue:     {Min: 1, Max: 999},
+	formatDate:   {Min: 2, Max: 3},
+	relativeTime: {Min: 1, Max: 1},
+	translate:    {Min: 1, Max: 2},
+	minimum:      {Min: 1, Max: 999},
+	maximum:      {Min: 1, Max: 999},
+	numRange:     {Min: 1, Max: 3},
 }
 
 type GlobalCallExpr struct {
@@ -0,0 +1,33 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/textwire/textwire/v4/pkg/token"
+)
+
+type LambdaExpr struct {
+	BaseNode
+	Params []*IdentExpr // (<Params>) => <Body>
+	Body   Expression
+}
+
+func NewLambdaExpr(tok token.Token, params []*IdentExpr) *LambdaExpr {
+	return &LambdaExpr{
+		BaseNode: NewBaseNode(tok),
+		Params:   params,
+	}
+}
+
+func (*LambdaExpr) expressionNode() {}
+func (*LambdaExpr) segmentNode()    {}
+
+func (le *LambdaExpr) String() string {
+	params := make([]string, len(le.Params))
+	for i := range le.Params {
+		params[i] = le.Params[i].String()
+	}
+
+	return fmt.Sprintf("((%s) => %s)", strings.Join(params, ", "), le.Body)
+}
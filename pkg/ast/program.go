@@ -14,10 +14,15 @@ type Program struct {
 	AbsPath    string
 	Chunks     []Chunk
 	Components []*CompDir
+	Caches     []*CacheDir
 	Reserves   map[string]*ReserveDir
 	Inserts    map[string]*InsertDir
 	Slots      map[string]*SlotDir
 
+	// Source is the content of the template file. It's shown in
+	// source excerpts of the error page.
+	Source string
+
 	// UseDir is used to reference the use directive in the program.
 	// We need it because the final program object must have a field UseDir.
 	// After parsing a program we link this pointer to program.UseDir.
@@ -28,6 +33,7 @@ func NewProgram(tok token.Token) *Program {
 	return &Program{
 		BaseNode:   NewBaseNode(tok),
 		Components: []*CompDir{},
+		Caches:     []*CacheDir{},
 		Inserts:    map[string]*InsertDir{},
 		Reserves:   map[string]*ReserveDir{},
 		Slots:      map[string]*SlotDir{},
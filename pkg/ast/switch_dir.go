@@ -0,0 +1,57 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/textwire/textwire/v4/pkg/token"
+)
+
+type SwitchDir struct {
+	BaseNode
+	Value        Expression
+	Cases        []*CaseDir
+	DefaultBlock *Block // @default<DefaultBlock>@end
+}
+
+func NewSwitchDir(tok token.Token) *SwitchDir {
+	return &SwitchDir{
+		BaseNode: NewBaseNode(tok),
+	}
+}
+
+func (*SwitchDir) chunkNode() {}
+
+func (sd *SwitchDir) String() string {
+	var out strings.Builder
+	out.Grow(20 + len(sd.Cases)*2)
+
+	fmt.Fprintf(&out, "@switch(%s)\n", sd.Value)
+
+	for _, c := range sd.Cases {
+		out.WriteString(c.String())
+	}
+
+	if sd.DefaultBlock != nil {
+		out.WriteString("@default\n")
+		out.WriteString(sd.DefaultBlock.String() + "\n")
+	}
+
+	out.WriteString("@end\n")
+
+	return out.String()
+}
+
+func (sd *SwitchDir) AllChunks() []Chunk {
+	chunks := make([]Chunk, 0)
+
+	for _, c := range sd.Cases {
+		chunks = append(chunks, c.AllChunks()...)
+	}
+
+	if sd.DefaultBlock != nil {
+		chunks = append(chunks, sd.DefaultBlock.AllChunks()...)
+	}
+
+	return chunks
+}
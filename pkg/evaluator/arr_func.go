@@ -1,10 +1,8 @@
 package evaluator
 
 import (
-	"errors"
-	"fmt"
+	"cmp"
 	"math/rand"
-	"reflect"
 	"slices"
 	"strings"
 	"time"
@@ -29,8 +27,7 @@ func arrJoinFunc(receiver value.Literal, args ...value.Literal) (value.Literal,
 	} else {
 		str, ok := args[0].(*value.Str)
 		if !ok {
-			msg := fmt.Sprintf(fail.ErrFuncFirstArgStr, value.ARR_VAL, "join")
-			return nil, errors.New(msg)
+			return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.ARR_VAL, "join")
 		}
 
 		separator = str.Val
@@ -79,14 +76,12 @@ func arrSliceFunc(receiver value.Literal, args ...value.Literal) (value.Literal,
 	elems := receiver.(*value.Arr).Elements
 
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.ARR_VAL, "slice")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.ARR_VAL, "slice")
 	}
 
 	startFrom, ok := args[0].(*value.Int)
 	if !ok {
-		msg := fmt.Sprintf(fail.ErrFuncFirstArgInt, value.ARR_VAL, "slice")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.ARR_VAL, "slice")
 	}
 
 	start := max(int(startFrom.Val), 0)
@@ -98,8 +93,7 @@ func arrSliceFunc(receiver value.Literal, args ...value.Literal) (value.Literal,
 
 	endAt, ok := args[1].(*value.Int)
 	if !ok {
-		msg := fmt.Sprintf(fail.ErrFuncSecondArgInt, value.ARR_VAL, "slice")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncSecondArgInt, value.ARR_VAL, "slice")
 	}
 
 	end := int(endAt.Val)
@@ -138,8 +132,7 @@ func arrShuffleFunc(receiver value.Literal, _ ...value.Literal) (value.Literal,
 // arrContainsFunc checks if the given arr contains the given element
 func arrContainsFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.ARR_VAL, "contains")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.ARR_VAL, "contains")
 	}
 
 	elems := receiver.(*value.Arr).Elements
@@ -147,33 +140,13 @@ func arrContainsFunc(receiver value.Literal, args ...value.Literal) (value.Liter
 		return FALSE, nil
 	}
 
-	target := args[0]
-
-	for _, el := range elems {
-		isObj := el.Type() == value.OBJ_VAL && target.Type() == value.OBJ_VAL
-		isArr := el.Type() == value.ARR_VAL && target.Type() == value.ARR_VAL
-
-		if isObj || isArr {
-			if reflect.DeepEqual(el, target) {
-				return TRUE, nil
-			}
-
-			continue
-		}
-
-		if el.Native() == target.Native() {
-			return TRUE, nil
-		}
-	}
-
-	return FALSE, nil
+	return nativeBoolToBoolObj(indexOf(elems, args[0]) != -1), nil
 }
 
 // arrAppendFunc appends the given elements to the given arr
 func arrAppendFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.ARR_VAL, "append")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.ARR_VAL, "append")
 	}
 
 	arr := receiver.(*value.Arr)
@@ -194,8 +167,7 @@ func arrAppendFunc(receiver value.Literal, args ...value.Literal) (value.Literal
 // arrPrependFunc prepends the given elements to the given arr
 func arrPrependFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.ARR_VAL, "prepend")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.ARR_VAL, "prepend")
 	}
 
 	arr := receiver.(*value.Arr)
@@ -209,3 +181,460 @@ func arrPrependFunc(receiver value.Literal, args ...value.Literal) (value.Litera
 
 	return &value.Arr{Elements: newElems}, nil
 }
+
+// arrSortFunc returns a copy of the given arr sorted in ascending order.
+// Numbers, strings and times are compared by value, other types keep
+// their order
+func arrSortFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	sorted := slices.Clone(receiver.(*value.Arr).Elements)
+	slices.SortStableFunc(sorted, compareValues)
+	return &value.Arr{Elements: sorted}, nil
+}
+
+// arrSortByFunc returns a copy of the given arr sorted by the given key of
+// the objects or by the result of the given lambda. Nested keys are
+// separated with dots, like "user.name"
+func arrSortByFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	sel, err := selectorArg("sortBy", args)
+	if err != nil {
+		return nil, err
+	}
+
+	elems := receiver.(*value.Arr).Elements
+	keys, err := selectAll(sel, elems)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, len(elems))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	slices.SortStableFunc(indexes, func(a, b int) int {
+		return compareValues(keys[a], keys[b])
+	})
+
+	sorted := make([]value.Literal, len(elems))
+	for i, idx := range indexes {
+		sorted[i] = elems[idx]
+	}
+
+	return &value.Arr{Elements: sorted}, nil
+}
+
+// arrUniqueFunc returns the given arr without duplicate elements
+func arrUniqueFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	elems := receiver.(*value.Arr).Elements
+	unique := make([]value.Literal, 0, len(elems))
+
+	for _, el := range elems {
+		if indexOf(unique, el) == -1 {
+			unique = append(unique, el)
+		}
+	}
+
+	return &value.Arr{Elements: unique}, nil
+}
+
+// arrFilterFunc returns the elements that are truthy. With a key or a
+// lambda it returns the elements where the key or the lambda result is
+// truthy, and with a key and a value the objects where the key is equal
+// to the value
+func arrFilterFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	elems := receiver.(*value.Arr).Elements
+	filtered := make([]value.Literal, 0, len(elems))
+
+	sel := selector(func(el value.Literal, _ int) (value.Literal, error) {
+		return el, nil
+	})
+
+	if len(args) > 0 {
+		s, err := selectorArg("filter", args)
+		if err != nil {
+			return nil, err
+		}
+		sel = s
+	}
+
+	for i, el := range elems {
+		val, err := sel(el, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(args) > 1 && !valuesEqual(val, args[1]) {
+			continue
+		}
+
+		if len(args) < 2 && !isTruthy(val) {
+			continue
+		}
+
+		filtered = append(filtered, el)
+	}
+
+	return &value.Arr{Elements: filtered}, nil
+}
+
+// arrMapFunc returns the values of the given key from every object in
+// the given arr, or the results of the given lambda for every element
+func arrMapFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	return pluck("map", receiver, args)
+}
+
+// arrPluckFunc returns the values of the given key from every object in
+// the given arr
+func arrPluckFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	return pluck("pluck", receiver, args)
+}
+
+// arrGroupByFunc groups the elements of the given arr into an object of
+// arrays by the value of the given key or by the result of the lambda
+func arrGroupByFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	sel, err := selectorArg("groupBy", args)
+	if err != nil {
+		return nil, err
+	}
+
+	elems := receiver.(*value.Arr).Elements
+	keys, err := selectAll(sel, elems)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string]value.Literal{}
+
+	for i, el := range elems {
+		// Keys are stored unescaped, they are escaped when printed
+		key := value.Format(keys[i], value.EscapeNone)
+
+		group, ok := groups[key].(*value.Arr)
+		if !ok {
+			group = &value.Arr{Elements: []value.Literal{}}
+			groups[key] = group
+		}
+
+		group.Elements = append(group.Elements, el)
+	}
+
+	return value.NewObj(groups), nil
+}
+
+// arrChunkFunc splits the given arr into arrays of the given size
+func arrChunkFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.ARR_VAL, "chunk")
+	}
+
+	size, ok := args[0].(*value.Int)
+	if !ok {
+		return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.ARR_VAL, "chunk")
+	}
+
+	if size.Val < 1 {
+		return nil, fail.Errorf(fail.ErrFuncPositiveArg, value.ARR_VAL, "chunk")
+	}
+
+	elems := receiver.(*value.Arr).Elements
+	chunks := make([]value.Literal, 0, len(elems)/int(size.Val)+1)
+
+	for chunk := range slices.Chunk(elems, int(size.Val)) {
+		chunks = append(chunks, &value.Arr{Elements: chunk})
+	}
+
+	return &value.Arr{Elements: chunks}, nil
+}
+
+// arrFirstFunc returns the first element of the given arr
+func arrFirstFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	elems := receiver.(*value.Arr).Elements
+	if len(elems) == 0 {
+		return NIL, nil
+	}
+
+	return elems[0], nil
+}
+
+// arrLastFunc returns the last element of the given arr
+func arrLastFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	elems := receiver.(*value.Arr).Elements
+	if len(elems) == 0 {
+		return NIL, nil
+	}
+
+	return elems[len(elems)-1], nil
+}
+
+// arrSumFunc returns the sum of the numbers in the given arr. With a key
+// it sums the values of the key in every object
+func arrSumFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	nums, err := numbers("sum", receiver, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var intSum int64
+	var floatSum float64
+	isFloat := false
+
+	for _, num := range nums {
+		switch num := num.(type) {
+		case *value.Int:
+			intSum += num.Val
+		case *value.Float:
+			floatSum += num.Val
+			isFloat = true
+		}
+	}
+
+	if isFloat {
+		return &value.Float{Val: floatSum + float64(intSum)}, nil
+	}
+
+	return &value.Int{Val: intSum}, nil
+}
+
+// arrAvgFunc returns the average of the numbers in the given arr. With a
+// key it uses the values of the key in every object
+func arrAvgFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	nums, err := numbers("avg", receiver, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nums) == 0 {
+		return NIL, nil
+	}
+
+	var sum float64
+	for _, num := range nums {
+		sum += toFloat(num)
+	}
+
+	return &value.Float{Val: sum / float64(len(nums))}, nil
+}
+
+// arrMinFunc returns the smallest number in the given arr. With a key it
+// uses the values of the key in every object
+func arrMinFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	nums, err := numbers("min", receiver, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nums) == 0 {
+		return NIL, nil
+	}
+
+	return slices.MinFunc(nums, compareValues), nil
+}
+
+// arrMaxFunc returns the biggest number in the given arr. With a key it
+// uses the values of the key in every object
+func arrMaxFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	nums, err := numbers("max", receiver, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nums) == 0 {
+		return NIL, nil
+	}
+
+	return slices.MaxFunc(nums, compareValues), nil
+}
+
+// arrIndexOfFunc returns the index of the given element in the given arr,
+// or -1 if the arr doesn't contain it
+func arrIndexOfFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.ARR_VAL, "indexOf")
+	}
+
+	index := indexOf(receiver.(*value.Arr).Elements, args[0])
+
+	return &value.Int{Val: int64(index)}, nil
+}
+
+// arrFlattenFunc flattens nested arrays of the given arr. The optional
+// argument limits how many levels are flattened
+func arrFlattenFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	depth := -1
+
+	if len(args) > 0 {
+		depthArg, ok := args[0].(*value.Int)
+		if !ok {
+			return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.ARR_VAL, "flatten")
+		}
+		depth = int(depthArg.Val)
+	}
+
+	return &value.Arr{Elements: flatten(receiver.(*value.Arr).Elements, depth)}, nil
+}
+
+func flatten(elems []value.Literal, depth int) []value.Literal {
+	flat := make([]value.Literal, 0, len(elems))
+
+	for _, el := range elems {
+		arr, ok := el.(*value.Arr)
+		if !ok || depth == 0 {
+			flat = append(flat, el)
+			continue
+		}
+
+		flat = append(flat, flatten(arr.Elements, depth-1)...)
+	}
+
+	return flat
+}
+
+func pluck(funcName string, receiver value.Literal, args []value.Literal) (value.Literal, error) {
+	sel, err := selectorArg(funcName, args)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := selectAll(sel, receiver.(*value.Arr).Elements)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value.Arr{Elements: values}, nil
+}
+
+// numbers returns the elements of the arr, or the values of the key or
+// the lambda results when it's given, and fails if any of them isn't a
+// number
+func numbers(
+	funcName string,
+	receiver value.Literal,
+	args []value.Literal,
+) ([]value.Literal, error) {
+	elems := receiver.(*value.Arr).Elements
+
+	if len(args) > 0 {
+		res, err := pluck(funcName, receiver, args)
+		if err != nil {
+			return nil, err
+		}
+		elems = res.(*value.Arr).Elements
+	}
+
+	for _, el := range elems {
+		if el.Type() != value.INT_VAL && el.Type() != value.FLOAT_VAL {
+			return nil, fail.Errorf(fail.ErrFuncNumericElems, value.ARR_VAL, funcName, el.Type())
+		}
+	}
+
+	return elems, nil
+}
+
+// selector returns the value that the array function works with for the
+// element with the given index
+type selector func(el value.Literal, index int) (value.Literal, error)
+
+// selectorArg returns the selector for the first argument, which is a key
+// of the objects, like "user.name", or a lambda, like `u => u.name`. The
+// lambda is called with the element and its index
+func selectorArg(funcName string, args []value.Literal) (selector, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.ARR_VAL, funcName)
+	}
+
+	switch arg := args[0].(type) {
+	case *value.Str:
+		path := strings.Split(arg.Val, ".")
+		return func(el value.Literal, _ int) (value.Literal, error) {
+			return findKeyPath(el, path), nil
+		}, nil
+	case *value.Lambda:
+		return func(el value.Literal, index int) (value.Literal, error) {
+			res := arg.Fn(el, &value.Int{Val: int64(index)})
+			if errVal, ok := res.(*value.Error); ok {
+				return nil, errVal.Err
+			}
+			return res, nil
+		}, nil
+	}
+
+	return nil, fail.Errorf(fail.ErrFuncFirstArgKey, value.ARR_VAL, funcName)
+}
+
+// selectAll returns the selected values of all the elements
+func selectAll(sel selector, elems []value.Literal) ([]value.Literal, error) {
+	values := make([]value.Literal, len(elems))
+
+	for i, el := range elems {
+		val, err := sel(el, i)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = val
+	}
+
+	return values, nil
+}
+
+// findKeyPath returns the value of the nested key in the given object,
+// or nil when the element isn't an object or doesn't have the key
+func findKeyPath(el value.Literal, path []string) value.Literal {
+	obj, ok := el.(*value.Obj)
+	if !ok {
+		return NIL
+	}
+
+	return findObjKey(path, obj.Pairs)
+}
+
+// indexOf returns the index of the target in the elements, or -1
+func indexOf(elems []value.Literal, target value.Literal) int {
+	return slices.IndexFunc(elems, func(el value.Literal) bool {
+		return valuesEqual(el, target)
+	})
+}
+
+// compareValues orders values by type first, nil, booleans, numbers,
+// strings, times and then everything else, and then by value
+func compareValues(a, b value.Literal) int {
+	if rankA, rankB := sortRank(a), sortRank(b); rankA != rankB {
+		return cmp.Compare(rankA, rankB)
+	}
+
+	switch a := a.(type) {
+	case *value.Bool:
+		return cmp.Compare(boolRank(a.Val), boolRank(b.(*value.Bool).Val))
+	case *value.Int, *value.Float:
+		return cmp.Compare(toFloat(a), toFloat(b))
+	case *value.Str:
+		return strings.Compare(a.Val, b.(*value.Str).Val)
+	case *value.Time:
+		return a.Val.Compare(b.(*value.Time).Val)
+	}
+
+	return 0
+}
+
+func sortRank(val value.Literal) int {
+	switch val.(type) {
+	case *value.Nil:
+		return 0
+	case *value.Bool:
+		return 1
+	case *value.Int, *value.Float:
+		return 2
+	case *value.Str:
+		return 3
+	case *value.Time:
+		return 4
+	}
+
+	return 5
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -114,6 +114,78 @@ func TestEvalArrFunctions(t *testing.T) {
 		{615, `{{ [1.0/0.0].json() }}`, "[null]"},
 		{616, `{{ [-1.0/0.0].json() }}`, "[null]"},
 		{617, `{{ [1.0, (0.0/0.0), 3.0].json() }}`, "[1.0,null,3.0]"},
+		// sort
+		{620, `{{ [3, 1, 2].sort() }}`, "1, 2, 3"},
+		{630, `{{ [2.5, 1, -3].sort() }}`, "-3, 1, 2.5"},
+		{640, `{{ ['Venti', 'Chiori', 'Raiden'].sort() }}`, "Chiori, Raiden, Venti"},
+		{650, `{{ ['b', 2, nil, 'a', 1].sort().json() }}`, `[null,1,2,&#34;a&#34;,&#34;b&#34;]`},
+		{660, `{{ [].sort() }}`, ""},
+		// sortBy
+		{
+			670,
+			`{{ [{n: 'Venti', a: 3}, {n: 'Chiori', a: 1}].sortBy('a').pluck('n') }}`,
+			"Chiori, Venti",
+		},
+		{
+			680,
+			`{{ [{u: {n: 'b'}}, {u: {n: 'a'}}, {}].sortBy('u.n').pluck('u.n').json() }}`,
+			"[null,&#34;a&#34;,&#34;b&#34;]",
+		},
+		// unique
+		{690, `{{ [1, 2, 1, 3, 2].unique() }}`, "1, 2, 3"},
+		{700, `{{ [[1], [1], {a: 1}, {a: 1}].unique().json() }}`, `[[1],{&#34;a&#34;:1}]`},
+		// filter
+		{710, `{{ [1, 0, '', 'a', nil, true, false].filter() }}`, "1, a, 1"},
+		{720, `{{ [{a: 1}, {a: 0}, {b: 1}].filter('a').len() }}`, "1"},
+		{730, `{{ [{r: 'x', n: 1}, {r: 'y', n: 2}].filter('r', 'y').pluck('n') }}`, "2"},
+		{740, `{{ [].filter('a') }}`, ""},
+		// map
+		{750, `{{ [{u: {n: 'a'}}, {u: {n: 'b'}}].map('u.n') }}`, "a, b"},
+		// groupBy
+		{
+			760,
+			`{{ [{r: 'x', n: 1}, {r: 'y', n: 2}, {r: 'x', n: 3}].groupBy('r').json().raw() }}`,
+			`{"x":[{"n":1,"r":"x"},{"n":3,"r":"x"}],"y":[{"n":2,"r":"y"}]}`,
+		},
+		// chunk
+		{770, `{{ [1, 2, 3, 4, 5].chunk(2).json() }}`, "[[1,2],[3,4],[5]]"},
+		{780, `{{ [1, 2, 3].chunk(2).len() }}`, "2"},
+		{790, `{{ [].chunk(3).len() }}`, "0"},
+		// first, last
+		{800, `{{ [1, 2, 3].first() }}`, "1"},
+		{810, `{{ [1, 2, 3].last() }}`, "3"},
+		{820, `{{ [].first() }}`, ""},
+		{830, `{{ [].last() }}`, ""},
+		// sum, avg, min, max
+		{840, `{{ [1, 2, 3].sum() }}`, "6"},
+		{850, `{{ [1, 2.5].sum() }}`, "3.5"},
+		{860, `{{ [].sum() }}`, "0"},
+		{870, `{{ [{p: 2}, {p: 3}].sum('p') }}`, "5"},
+		{880, `{{ [1, 2, 3, 4].avg() }}`, "2.5"},
+		{890, `{{ [].avg() }}`, ""},
+		{900, `{{ [3, -1, 2.5].min() }}`, "-1"},
+		{910, `{{ [3, -1, 2.5].max() }}`, "3"},
+		{920, `{{ [{p: 2}, {p: 3}].max('p') }}`, "3"},
+		// pluck
+		{
+			930,
+			`{{ [{n: 'Venti'}, {n: 'Chiori'}, 5].pluck('n').json() }}`,
+			`[&#34;Venti&#34;,&#34;Chiori&#34;,null]`,
+		},
+		// indexOf
+		{940, `{{ [1, 2, 3].indexOf(3) }}`, "2"},
+		{950, `{{ [1, 2, 3].indexOf(4) }}`, "-1"},
+		{960, `{{ [[1], {a: 1}].indexOf({a: 1}) }}`, "1"},
+		// flatten
+		{970, `{{ [1, [2, [3, [4]]]].flatten().json() }}`, "[1,2,3,4]"},
+		{980, `{{ [1, [2, [3, [4]]]].flatten(1).json() }}`, "[1,2,[3,[4]]]"},
+		{990, `{{ [].flatten().len() }}`, "0"},
+		// numeric promotion when comparing elements
+		{1000, `{{ [1, 2, 3].contains(2.0) }}`, "1"},
+		{1010, `{{ [1, 2.0, 2, 1.0].unique().json() }}`, "[1,2.0]"},
+		{1020, `{{ [[1], [2]].indexOf([2.0]) }}`, "1"},
+		{1030, `{{ [{d: 'R&D'}, {d: 'R&D'}].groupBy('d').get('R&D').len() }}`, "2"},
+		{1040, `{{ [{d: '<a "b">'}].groupBy('d').keys() }}`, "&lt;a &#34;b&#34;&gt;"},
 	}
 
 	for _, tc := range cases {
@@ -1,9 +1,6 @@
 package evaluator
 
 import (
-	"errors"
-	"fmt"
-
 	"github.com/textwire/textwire/v4/pkg/fail"
 	"github.com/textwire/textwire/v4/pkg/value"
 )
@@ -20,8 +17,7 @@ func boolBinaryFunc(receiver value.Literal, _ ...value.Literal) (value.Literal,
 // boolThenFunc returns the first argument if the receiver is true, the second argument or nil otherwise
 func boolThenFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.BOOL_VAL, "then")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.BOOL_VAL, "then")
 	}
 
 	if isTruthy(receiver) {
@@ -1,15 +1,30 @@
 package evaluator
 
 import (
-	"reflect"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/textwire/textwire/v4/config"
 	"github.com/textwire/textwire/v4/pkg/ast"
 	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/hooks"
+	"github.com/textwire/textwire/v4/pkg/i18n"
+	"github.com/textwire/textwire/v4/pkg/position"
+	"github.com/textwire/textwire/v4/pkg/profile"
 	"github.com/textwire/textwire/v4/pkg/value"
 )
 
+// timeNow returns the current time for relativeTime().
+var timeNow = time.Now
+
+// maxRangeLen limits how many elements range() can create, so that
+// a template can't allocate huge arrays.
+const maxRangeLen = 100_000
+
 var (
 	NIL      = &value.Nil{}
 	TRUE     = &value.Bool{Val: true}
@@ -26,14 +41,74 @@ type Evaluator struct {
 	// config can be nil when Textwire is used for simple string and
 	// file evaluation. If config is not nil, it means we use templates.
 	config *config.Config
+
+	// frames is the template call stack with the outermost frame first.
+	// It's attached to every error created during evaluation.
+	frames []fail.Frame
+
+	// checkingDefined is true while defined() evaluates its arguments.
+	// Undefined variables always produce errors then, regardless of
+	// the Undefined mode in the config.
+	checkingDefined bool
+
+	// hooks are notified about rendered components and function calls.
+	// hooksCtx is the context returned by the last start hook.
+	hooks    hooks.Hooks
+	hooksCtx context.Context
+
+	// profiler measures time of loops, function calls and components.
+	// It's nil when templates are not profiled.
+	profiler *profile.Recorder
+
+	// locale is used by __() to translate messages. When it's empty,
+	// the locale from the config is used.
+	locale string
+
+	// mode is the output mode of the page that is being rendered.
+	// Layouts and components inherit it from the page.
+	mode config.OutputMode
 }
 
 func New(customFunc *config.Func, conf *config.Config) *Evaluator {
-	return &Evaluator{
+	e := &Evaluator{
 		customFunc:     customFunc,
 		config:         conf,
 		usingTemplates: conf != nil,
 	}
+
+	if conf != nil {
+		e.mode = conf.Output
+	}
+
+	return e
+}
+
+// WithHooks sets hooks that are notified about rendered components and
+// function calls. The ctx is the one returned by Hooks.OnRenderStart.
+func (e *Evaluator) WithHooks(ctx context.Context, h hooks.Hooks) *Evaluator {
+	e.hooks = h
+	e.hooksCtx = ctx
+	return e
+}
+
+// WithProfiler sets the recorder that measures time of loops,
+// function calls and components.
+func (e *Evaluator) WithProfiler(r *profile.Recorder) *Evaluator {
+	e.profiler = r
+	return e
+}
+
+// WithLocale sets the locale that is used to translate messages.
+func (e *Evaluator) WithLocale(locale string) *Evaluator {
+	e.locale = locale
+	return e
+}
+
+// WithOutput sets the output mode of the page that is being rendered.
+// It's Output from the config by default.
+func (e *Evaluator) WithOutput(mode config.OutputMode) *Evaluator {
+	e.mode = mode
+	return e
 }
 
 func (e *Evaluator) Eval(node ast.Node, ctx *Context) value.Value {
@@ -76,10 +151,14 @@ func (e *Evaluator) evalValue(node ast.Node, ctx *Context) value.Value {
 		return NIL
 	case *ast.DumpDir:
 		return e.dumpDir(node, ctx)
+	case *ast.CacheDir:
+		return e.cacheDir(node, ctx)
 	case *ast.InsertDir:
 		return e.insertDir(node, ctx)
 	case *ast.IfDir:
 		return e.ifDir(node, ctx)
+	case *ast.SwitchDir:
+		return e.switchDir(node, ctx)
 	case *ast.ForDir:
 		return e.forDir(node, ctx)
 	case *ast.EachDir:
@@ -124,6 +203,8 @@ func (e *Evaluator) evalLiteral(node ast.Node, ctx *Context) value.Literal {
 		return e.callExpr(node, ctx)
 	case *ast.GlobalCallExpr:
 		return e.globalCallExpr(node, ctx)
+	case *ast.LambdaExpr:
+		return e.lambdaExpr(node, ctx)
 	case *ast.IntExpr:
 		return &value.Int{Val: node.Val}
 	case *ast.FloatExpr:
@@ -136,6 +217,12 @@ func (e *Evaluator) evalLiteral(node ast.Node, ctx *Context) value.Literal {
 }
 
 func (e *Evaluator) program(prog *ast.Program, ctx *Context) value.Value {
+	// The first evaluated program is the page, layouts and components
+	// push their frames before they are evaluated.
+	if e.usingTemplates && len(e.frames) == 0 {
+		defer e.pushFrame(fail.FramePage, prog.Name, prog.AbsPath, nil)()
+	}
+
 	block := value.NewBlock(len(prog.Chunks))
 
 	for i := range prog.Chunks {
@@ -151,12 +238,17 @@ func (e *Evaluator) program(prog *ast.Program, ctx *Context) value.Value {
 
 func (e *Evaluator) embedded(embeddedAst *ast.Embedded, ctx *Context) value.Value {
 	embedded := value.NewEmbedded(len(embeddedAst.Segments))
-
 	for _, segment := range embeddedAst.Segments {
 		segment := e.evalLiteral(segment, ctx)
 		if isError(segment) {
 			return segment
 		}
+
+		if e.mode != config.OutputHTML {
+			out := value.Format(segment, escaper(e.mode))
+			segment = &value.Str{Val: out, IsRaw: true}
+		}
+
 		embedded.Segments = append(embedded.Segments, segment)
 	}
 
@@ -194,6 +286,36 @@ func (e *Evaluator) ifDir(ifDir *ast.IfDir, ctx *Context) value.Value {
 	return NIL
 }
 
+// switchDir renders the block of the first case that has a value equal
+// to the switch value. Cases don't fall through to the next one.
+func (e *Evaluator) switchDir(switchDir *ast.SwitchDir, ctx *Context) value.Value {
+	val := e.evalLiteral(switchDir.Value, ctx)
+	if isError(val) {
+		return val
+	}
+
+	switchCtx := NewContext(ctx.scope.Child(), ctx.absPath)
+
+	for _, caseDir := range switchDir.Cases {
+		for _, caseExpr := range caseDir.Values {
+			caseVal := e.evalLiteral(caseExpr, switchCtx)
+			if isError(caseVal) {
+				return caseVal
+			}
+
+			if valuesEqual(val, caseVal) {
+				return e.Eval(caseDir.Block, switchCtx)
+			}
+		}
+	}
+
+	if switchDir.DefaultBlock != nil {
+		return e.Eval(switchDir.DefaultBlock, switchCtx)
+	}
+
+	return NIL
+}
+
 func (e *Evaluator) block(astBlock *ast.Block, ctx *Context) value.Value {
 	if astBlock == nil {
 		return NIL
@@ -254,7 +376,7 @@ func (e *Evaluator) assignIdent(
 	ctx *Context,
 ) value.Literal {
 	if err := ctx.scope.Set(ident.Name, val); err != nil {
-		return e.newError(ident, ctx, "%s", err.Error())
+		return e.wrapError(ident, ctx, err)
 	}
 	return NIL
 }
@@ -338,9 +460,14 @@ func (e *Evaluator) useDir(useDir *ast.UseDir, ctx *Context) value.Value {
 		return e.newError(useDir.LayoutProg.UseDir, layoutCtx, fail.ErrUseDirIsNotAllowed)
 	}
 
+	defer e.pushFrame(fail.FrameLayout, useDir.Name.Val, ctx.absPath, useDir.Pos())()
+
 	// Evaluate @inserts and map them into new context for layout
 	for name, insertDir := range useDir.Inserts {
+		popFrame := e.pushFrame(fail.FrameInsert, name, ctx.absPath, insertDir.Pos())
 		insert := e.insertDir(insertDir, ctx)
+		popFrame()
+
 		if isError(insert) {
 			return insert
 		}
@@ -390,6 +517,46 @@ func (e *Evaluator) compDir(compDir *ast.CompDir, ctx *Context) value.Value {
 		return e.newError(compDir, ctx, fail.ErrUndefinedComponent, name)
 	}
 
+	defer e.pushFrame(fail.FrameComponent, name, ctx.absPath, compDir.Pos())()
+
+	if e.profiler != nil {
+		e.profiler.Enter(profile.KindComponent, name, ctx.absPath, compDir.Pos())
+		defer e.profiler.Exit()
+	}
+
+	if e.hooks == nil {
+		return e.component(compDir, ctx)
+	}
+
+	parentCtx := e.hooksCtx
+	defer func() { e.hooksCtx = parentCtx }()
+
+	event := hooks.Component{
+		Name:   name,
+		Path:   compDir.CompProg.AbsPath,
+		Caller: ctx.absPath,
+		Pos:    compDir.Pos(),
+		Start:  time.Now(),
+	}
+
+	e.hooksCtx = e.hooks.OnComponentStart(parentCtx, event)
+	result := e.component(compDir, ctx)
+	event.Duration = time.Since(event.Start)
+
+	if err, ok := result.(*value.Error); ok {
+		event.Err = err.Err
+	} else {
+		event.Size = len(result.String())
+	}
+
+	e.hooks.OnComponentEnd(e.hooksCtx, event)
+
+	return result
+}
+
+// component evaluates the component program with passed slots and arguments.
+func (e *Evaluator) component(compDir *ast.CompDir, ctx *Context) value.Value {
+	name := compDir.Name.Val
 	compCtx := NewContext(value.NewScope(), compDir.CompProg.AbsPath)
 
 	if compCtx.slots[name] == nil {
@@ -431,7 +598,10 @@ func (e *Evaluator) evalCompDirPasses(compDir *ast.CompDir, ctx, compCtx *Contex
 			}
 		}
 
+		popFrame := e.pushFrame(fail.FrameSlot, passDir.Name.Val, ctx.absPath, passDir.Pos())
 		passBlock := e.block(passDir.Block, ctx)
+		popFrame()
+
 		if isError(passBlock) {
 			return passBlock
 		}
@@ -441,7 +611,11 @@ func (e *Evaluator) evalCompDirPasses(compDir *ast.CompDir, ctx, compCtx *Contex
 
 	// Save content from default @pass
 	if compDir.DefaultPass != nil {
-		passBlock := e.block(compDir.DefaultPass.Block, ctx)
+		defaultPass := compDir.DefaultPass
+		popFrame := e.pushFrame(fail.FrameSlot, "", ctx.absPath, defaultPass.Pos())
+		passBlock := e.block(defaultPass.Block, ctx)
+		popFrame()
+
 		if isError(passBlock) {
 			return passBlock
 		}
@@ -459,13 +633,19 @@ func (e *Evaluator) passCompArgsToCtx(compDir *ast.CompDir, ctx, compCtx *Contex
 		}
 
 		if err := compCtx.scope.Set(key, obj); err != nil {
-			return e.newError(compDir, ctx, "%s", err.Error())
+			return e.wrapError(compDir, ctx, err)
 		}
 	}
 	return nil
 }
 
 func (e *Evaluator) forDir(forDir *ast.ForDir, ctx *Context) value.Value {
+	if e.profiler != nil {
+		name := fmt.Sprintf("@for(%s; %s; %s)", forDir.Init, forDir.Cond, forDir.Post)
+		e.profiler.Enter(profile.KindLoop, name, ctx.absPath, forDir.Pos())
+		defer e.profiler.Exit()
+	}
+
 	forCtx := NewContext(ctx.scope, ctx.absPath)
 
 	init := e.evalLiteral(forDir.Init, forCtx)
@@ -520,6 +700,12 @@ func (e *Evaluator) forDir(forDir *ast.ForDir, ctx *Context) value.Value {
 }
 
 func (e *Evaluator) eachDir(eachDir *ast.EachDir, ctx *Context) value.Value {
+	if e.profiler != nil {
+		name := fmt.Sprintf("@each(%s in %s)", eachDir.Var, eachDir.Arr)
+		e.profiler.Enter(profile.KindLoop, name, ctx.absPath, eachDir.Pos())
+		defer e.profiler.Exit()
+	}
+
 	eachCtx := NewContext(ctx.scope.Child(), ctx.absPath)
 	varName := eachDir.Var.Name
 
@@ -544,7 +730,7 @@ func (e *Evaluator) eachDir(eachDir *ast.EachDir, ctx *Context) value.Value {
 
 	for i := range arrElems {
 		if err := eachCtx.scope.Set(varName, arrElems[i]); err != nil {
-			return e.newError(eachDir, eachCtx, "%s", err.Error())
+			return e.wrapError(eachDir, eachCtx, err)
 		}
 
 		eachCtx.scope.SetLoopVar(map[string]value.Literal{
@@ -655,8 +841,96 @@ func (e *Evaluator) combineInsertContent(insertDir *ast.InsertDir, ctx *Context)
 	return e.Eval(insertDir.Block, ctx)
 }
 
+// cacheDir returns content of the block from config.Cache or evaluates
+// it and stores it there. Content is not cached outside of templates.
+func (e *Evaluator) cacheDir(cacheDir *ast.CacheDir, ctx *Context) value.Value {
+	if cacheDir.Block == nil {
+		return NIL
+	}
+
+	if e.config == nil || e.config.Cache == nil {
+		return e.block(cacheDir.Block, ctx)
+	}
+
+	key, ttl, failure := e.cacheKeyAndTTL(cacheDir, ctx)
+	if failure != nil {
+		return failure
+	}
+
+	if content, ok := e.config.Cache.Get(key); ok {
+		return &value.Text{Val: content}
+	}
+
+	block := e.block(cacheDir.Block, ctx)
+	if isError(block) {
+		return block
+	}
+
+	e.config.Cache.Set(key, block.String(), ttl)
+
+	return block
+}
+
+// cacheKeyAndTTL evaluates arguments of @cache directive. The returned key
+// is unique for the directive and changes every time templates are linked.
+func (e *Evaluator) cacheKeyAndTTL(
+	cacheDir *ast.CacheDir,
+	ctx *Context,
+) (string, time.Duration, value.Value) {
+	keyVal := e.evalLiteral(cacheDir.Key, ctx)
+	if isError(keyVal) {
+		return "", 0, keyVal
+	}
+
+	var key string
+	switch k := keyVal.(type) {
+	case *value.Str:
+		key = k.Val
+	case *value.Int, *value.Float:
+		key = k.String()
+	default:
+		return "", 0, e.newError(cacheDir.Key, ctx, fail.ErrCacheKeyType, keyVal.Type())
+	}
+
+	var ttl time.Duration
+	if cacheDir.TTL != nil {
+		ttlVal := e.evalLiteral(cacheDir.TTL, ctx)
+		if isError(ttlVal) {
+			return "", 0, ttlVal
+		}
+
+		switch t := ttlVal.(type) {
+		case *value.Int:
+			ttl = time.Duration(t.Val) * time.Second
+		case *value.Str:
+			d, err := time.ParseDuration(t.Val)
+			if err != nil {
+				return "", 0, e.newError(cacheDir.TTL, ctx, fail.ErrCacheTTLType, t.Val)
+			}
+			ttl = d
+		default:
+			return "", 0, e.newError(cacheDir.TTL, ctx, fail.ErrCacheTTLType, ttlVal.Type())
+		}
+	}
+
+	pos := cacheDir.Pos()
+	key = fmt.Sprintf(
+		"textwire:%s:%d:%d:%d:%s:%s:%s",
+		ctx.absPath,
+		pos.StartLine,
+		pos.StartCol,
+		cacheDir.Generation,
+		e.currentLocale(),
+		e.mode,
+		key,
+	)
+
+	return key, ttl, nil
+}
+
 func (e *Evaluator) dumpDir(dumpDir *ast.DumpDir, ctx *Context) value.Value {
 	dump := value.NewDump(len(dumpDir.Args))
+	dump.Plain = e.mode != config.OutputHTML
 
 	for i := range dumpDir.Args {
 		evaluated := e.evalLiteral(dumpDir.Args[i], ctx)
@@ -676,12 +950,40 @@ func (e *Evaluator) identExpr(ident *ast.IdentExpr, ctx *Context) value.Literal
 		return val
 	}
 
-	return e.newError(ident, ctx, fail.ErrVariableIsUndefined, ident.Name)
+	return e.undefined(ident, ctx, fail.ErrVariableIsUndefined, ident.Name)
+}
+
+// lambdaExpr creates a lambda that evaluates its body in a child of the
+// scope where the lambda is defined. Missing arguments are nil.
+func (e *Evaluator) lambdaExpr(lambda *ast.LambdaExpr, ctx *Context) value.Literal {
+	params := make([]string, len(lambda.Params))
+	for i := range lambda.Params {
+		params[i] = lambda.Params[i].Name
+	}
+
+	fn := func(args ...value.Literal) value.Literal {
+		lambdaCtx := NewContext(ctx.scope.Child(), ctx.absPath)
+
+		for i, param := range params {
+			var arg value.Literal = NIL
+			if i < len(args) {
+				arg = args[i]
+			}
+
+			if err := lambdaCtx.scope.Define(param, arg); err != nil {
+				return e.wrapError(lambda.Params[i], ctx, err)
+			}
+		}
+
+		return e.evalLiteral(lambda.Body, lambdaCtx)
+	}
+
+	return &value.Lambda{Params: params, Fn: fn}
 }
 
 func (e *Evaluator) indexExpr(indexExp *ast.IndexExpr, ctx *Context) value.Literal {
-	left := e.evalLiteral(indexExp.Left, ctx)
-	if isError(left) {
+	left, undefined := e.receiver(indexExp, indexExp.Left, ctx)
+	if isError(left) || undefined {
 		return left
 	}
 
@@ -694,7 +996,7 @@ func (e *Evaluator) indexExpr(indexExp *ast.IndexExpr, ctx *Context) value.Liter
 	case left.Is(value.ARR_VAL) && idx.Is(value.INT_VAL):
 		return e.arrIndexExp(left, idx)
 	case left.Is(value.OBJ_VAL) && idx.Is(value.STR_VAL):
-		return e.objKeyExp(left.(*value.Obj), idx.(*value.Str).Val)
+		return e.objKeyExp(indexExp, ctx, left.(*value.Obj), idx.(*value.Str).Val)
 	}
 
 	return e.newError(indexExp, ctx, fail.ErrIndexNotSupported, left.Type())
@@ -712,7 +1014,12 @@ func (e *Evaluator) arrIndexExp(arrObj, idx value.Literal) value.Literal {
 	return arr.Elements[index]
 }
 
-func (e *Evaluator) objKeyExp(obj *value.Obj, key string) value.Literal {
+func (e *Evaluator) objKeyExp(
+	node ast.Node,
+	ctx *Context,
+	obj *value.Obj,
+	key string,
+) value.Literal {
 	if pair, ok := obj.Pairs[key]; ok {
 		return pair
 	}
@@ -723,12 +1030,82 @@ func (e *Evaluator) objKeyExp(obj *value.Obj, key string) value.Literal {
 		return pair
 	}
 
+	if e.config != nil && e.config.StrictKeys {
+		return e.undefined(node, ctx, fail.ErrObjKeyIsUndefined, key)
+	}
+
 	return NIL
 }
 
+// undefined handles an undefined variable or a missing object key with the
+// given name. It returns an error unless the config chooses to render
+// an empty string or a placeholder instead.
+func (e *Evaluator) undefined(
+	node ast.Node,
+	ctx *Context,
+	format, name string,
+) value.Literal {
+	err := e.newError(node, ctx, format, name)
+	if e.config == nil || e.config.Undefined == config.UndefinedError || e.checkingDefined {
+		return err
+	}
+
+	msg := "undefined variable"
+	if format == fail.ErrObjKeyIsUndefined {
+		msg = "undefined object key"
+	}
+
+	e.config.Log().LogAttrs(context.Background(), slog.LevelWarn, msg, err.Err.LogAttrs()...)
+
+	if e.config.OnUndefined != nil {
+		e.config.OnUndefined(err.Err)
+	}
+
+	if e.config.Undefined == config.UndefinedPlaceholder {
+		return &value.Str{Val: strings.ReplaceAll(e.config.UndefinedPlaceholder, "%s", name)}
+	}
+
+	return NIL
+}
+
+// lenientUndefined reports whether undefined values are replaced instead
+// of failing the evaluation.
+func (e *Evaluator) lenientUndefined() bool {
+	return e.config != nil && e.config.Undefined != config.UndefinedError && !e.checkingDefined
+}
+
+// receiver evaluates the left side of a member or index access. When
+// undefined values are replaced and the receiver is undefined or nil,
+// the whole access is handled as undefined, so `user.name` renders
+// a single placeholder instead of failing. The bool is true then.
+func (e *Evaluator) receiver(
+	access, left ast.Expression,
+	ctx *Context,
+) (value.Literal, bool) {
+	if !e.lenientUndefined() {
+		return e.evalLiteral(left, ctx), false
+	}
+
+	evaluated := e.evalDefined(left, ctx)
+	if evaluated != NIL && !isUndefinedError(evaluated) {
+		return evaluated, false
+	}
+
+	return e.undefined(access, ctx, fail.ErrVariableIsUndefined, accessName(access)), true
+}
+
+// evalDefined evaluates the expression like defined() does, undefined
+// variables in it give errors instead of being replaced.
+func (e *Evaluator) evalDefined(node ast.Expression, ctx *Context) value.Literal {
+	defer func(checking bool) { e.checkingDefined = checking }(e.checkingDefined)
+	e.checkingDefined = true
+
+	return e.evalLiteral(node, ctx)
+}
+
 func (e *Evaluator) dotExpr(dotExp *ast.DotExpr, ctx *Context) value.Literal {
-	left := e.evalLiteral(dotExp.Left, ctx)
-	if isError(left) {
+	left, undefined := e.receiver(dotExp, dotExp.Left, ctx)
+	if isError(left) || undefined {
 		return left
 	}
 
@@ -738,7 +1115,7 @@ func (e *Evaluator) dotExpr(dotExp *ast.DotExpr, ctx *Context) value.Literal {
 		return e.newError(dotExp, ctx, fail.ErrKeyOnNonObj, left.Type(), key)
 	}
 
-	return e.objKeyExp(obj, key.Name)
+	return e.objKeyExp(dotExp, ctx, obj, key.Name)
 }
 
 func (e *Evaluator) strExpr(strLit *ast.StrExpr) value.Literal {
@@ -897,8 +1274,7 @@ func (e *Evaluator) callExpr(callExp *ast.CallExpr, ctx *Context) value.Literal
 	}
 
 	receiverType := receiver.Type()
-	typeFuncs, ok := functions[receiverType]
-	if !ok {
+	if _, ok := functions[receiverType]; !ok {
 		return e.newError(callExp, ctx, fail.ErrFuncNotDefined, receiverType, funcName)
 	}
 
@@ -907,11 +1283,42 @@ func (e *Evaluator) callExpr(callExp *ast.CallExpr, ctx *Context) value.Literal
 		return args[0]
 	}
 
-	buitin, ok := typeFuncs[callExp.Function.Name]
+	return e.traceCall(callExp, ctx, string(receiverType), funcName, func() value.Literal {
+		return e.callFunc(callExp, ctx, receiver, args)
+	})
+}
+
+// callFunc calls the built-in or custom function of the receiver.
+func (e *Evaluator) callFunc(
+	callExp *ast.CallExpr,
+	ctx *Context,
+	receiver value.Literal,
+	args []value.Literal,
+) value.Literal {
+	receiverType := receiver.Type()
+	funcName := callExp.Function.Name
+
+	buitin, ok := functions[receiverType][funcName]
 	if ok {
 		result, err := buitin.Fn(receiver, args...)
 		if err != nil {
-			return e.newError(callExp, ctx, "%s", err.Error())
+			return e.wrapError(callExp, ctx, err)
+		}
+		return result
+	}
+
+	if fn, ok := localeFunctions[receiverType][funcName]; ok {
+		result, err := fn(e.formatLocale(), receiver, args...)
+		if err != nil {
+			return e.wrapError(callExp, ctx, err)
+		}
+		return result
+	}
+
+	if fn, ok := outputFunctions[receiverType][funcName]; ok {
+		result, err := fn(e.mode, receiver, args...)
+		if err != nil {
+			return e.wrapError(callExp, ctx, err)
 		}
 		return result
 	}
@@ -923,36 +1330,114 @@ func (e *Evaluator) callExpr(callExp *ast.CallExpr, ctx *Context) value.Literal
 		case *value.Str:
 			fun := e.customFunc.Str[funcName]
 			res := fun(r.String(), nativeArgs...)
-			return value.NativeToValue(res)
+			return e.customFuncResult(callExp, ctx, receiverType, res)
 		case *value.Arr:
 			fun := e.customFunc.Arr[funcName]
 			nativeElems := e.valuesToNativeType(r.Elements)
 			res := fun(nativeElems, nativeArgs...)
-			return value.NativeToValue(res)
+			return e.customFuncResult(callExp, ctx, receiverType, res)
 		case *value.Int:
 			fun := e.customFunc.Int[funcName]
 			res := fun(int(r.Val), nativeArgs...)
-			return value.NativeToValue(res)
+			return e.customFuncResult(callExp, ctx, receiverType, res)
 		case *value.Float:
 			fun := e.customFunc.Float[funcName]
 			res := fun(r.Val, nativeArgs...)
-			return value.NativeToValue(res)
+			return e.customFuncResult(callExp, ctx, receiverType, res)
 		case *value.Bool:
 			fun := e.customFunc.Bool[funcName]
 			res := fun(r.Val, nativeArgs...)
-			return value.NativeToValue(res)
+			return e.customFuncResult(callExp, ctx, receiverType, res)
 		case *value.Obj:
 			fun := e.customFunc.Obj[funcName]
 			firstArg := r.Native()
 			res := fun(firstArg.(map[string]any), nativeArgs...)
-			return value.NativeToValue(res)
+			return e.customFuncResult(callExp, ctx, receiverType, res)
 		}
 	}
 
 	return e.newError(callExp, ctx, fail.ErrFuncNotDefined, receiver.Type(), callExp.Function.Name)
 }
 
+// customFuncResult converts the result of a custom function into a value.
+// When the function returns an error, evaluation fails with that error.
+func (e *Evaluator) customFuncResult(
+	callExp *ast.CallExpr,
+	ctx *Context,
+	receiverType value.ValueType,
+	res any,
+) value.Literal {
+	err, ok := res.(error)
+	if !ok {
+		return value.NativeToValue(res)
+	}
+
+	failure := fail.Wrap(
+		err,
+		callExp.Pos(),
+		ctx.absPath,
+		fail.OriginEval,
+		fail.ErrCustomFuncFailed,
+		receiverType,
+		callExp.Function.Name,
+		err,
+	)
+
+	return &value.Error{
+		Err:     e.withDetails(failure, ctx),
+		ErrorID: fail.ErrCustomFuncFailed,
+	}
+}
+
 func (e *Evaluator) globalCallExpr(globalCallExp *ast.GlobalCallExpr, ctx *Context) value.Literal {
+	return e.traceCall(globalCallExp, ctx, "", string(globalCallExp.Name), func() value.Literal {
+		return e.globalFunc(globalCallExp, ctx)
+	})
+}
+
+// traceCall calls the function, notifies hooks about the call
+// and measures it when profiling.
+func (e *Evaluator) traceCall(
+	node ast.Node,
+	ctx *Context,
+	receiver, name string,
+	call func() value.Literal,
+) value.Literal {
+	if e.profiler != nil {
+		label := name + "()"
+		if receiver != "" {
+			label = receiver + "." + label
+		}
+
+		e.profiler.Enter(profile.KindCall, label, ctx.absPath, node.Pos())
+		defer e.profiler.Exit()
+	}
+
+	if e.hooks == nil {
+		return call()
+	}
+
+	event := hooks.FunctionCall{
+		Receiver: receiver,
+		Name:     name,
+		Path:     ctx.absPath,
+		Pos:      node.Pos(),
+		Start:    time.Now(),
+	}
+
+	result := call()
+	event.Duration = time.Since(event.Start)
+
+	if err, ok := result.(*value.Error); ok {
+		event.Err = err.Err
+	}
+
+	e.hooks.OnFunctionCall(e.hooksCtx, event)
+
+	return result
+}
+
+func (e *Evaluator) globalFunc(globalCallExp *ast.GlobalCallExpr, ctx *Context) value.Literal {
 	switch globalCallExp.Name {
 	case "defined":
 		return e.globalFuncDefined(globalCallExp, ctx)
@@ -960,6 +1445,16 @@ func (e *Evaluator) globalCallExpr(globalCallExp *ast.GlobalCallExpr, ctx *Conte
 		return e.globalFuncHasValue(globalCallExp, ctx)
 	case "formatDate":
 		return e.globalFuncFormatDate(globalCallExp, ctx)
+	case "relativeTime":
+		return e.globalFuncRelativeTime(globalCallExp, ctx)
+	case "__":
+		return e.globalFuncTranslate(globalCallExp, ctx)
+	case "min":
+		return e.globalFuncMinMax(globalCallExp, ctx, slices.MinFunc)
+	case "max":
+		return e.globalFuncMinMax(globalCallExp, ctx, slices.MaxFunc)
+	case "range":
+		return e.globalFuncRange(globalCallExp, ctx)
 	default:
 		return e.newError(
 			globalCallExp,
@@ -974,6 +1469,9 @@ func (e *Evaluator) globalFuncDefined(
 	globalCallExp *ast.GlobalCallExpr,
 	ctx *Context,
 ) value.Literal {
+	defer func(checking bool) { e.checkingDefined = checking }(e.checkingDefined)
+	e.checkingDefined = true
+
 	for i := range globalCallExp.Arguments {
 		evaluated := e.evalLiteral(globalCallExp.Arguments[i], ctx)
 		if isUndefinedError(evaluated) {
@@ -1022,9 +1520,13 @@ func (e *Evaluator) globalFuncFormatDate(
 	globalCallExpr *ast.GlobalCallExpr,
 	ctx *Context,
 ) value.Literal {
-	// Note: We already know that we have 2 arguments since parser ensures that
+	// Note: We already know that we have 2 or 3 arguments since parser ensures that
 
-	date, err := e.globalCallExpectStrArg(globalCallExpr, ctx, 0)
+	date, isEmpty, err := e.globalCallExpectDateArg(
+		globalCallExpr,
+		ctx,
+		fail.ErrFormatDateWrongDate,
+	)
 	if err != nil {
 		return err
 	}
@@ -1034,21 +1536,160 @@ func (e *Evaluator) globalFuncFormatDate(
 		return err
 	}
 
-	if date.Val == "" {
+	if isEmpty {
 		return &value.Str{Val: ""}
 	}
 
-	parseLayout := getDateTimeLayout(date.Val)
-	if parseLayout == "" {
-		return e.newError(globalCallExpr, ctx, fail.ErrFormatDateWrongDate, date.Val)
+	if len(globalCallExpr.Arguments) == 3 {
+		tz, err := e.globalCallExpectStrArg(globalCallExpr, ctx, 2)
+		if err != nil {
+			return err
+		}
+
+		loc, loadErr := time.LoadLocation(tz.Val)
+		if loadErr != nil {
+			return e.newError(globalCallExpr, ctx, fail.ErrUnknownTimeZone, tz.Val)
+		}
+
+		date = date.In(loc)
 	}
 
-	newFormat, parseErr := time.Parse(parseLayout, date.Val)
-	if parseErr != nil {
-		return e.newError(globalCallExpr, ctx, fail.ErrFormatDateParseErr, date.Val, layout.Val)
+	return &value.Str{Val: e.formatLocale().FormatDate(date, layout.Val)}
+}
+
+func (e *Evaluator) globalFuncRelativeTime(
+	globalCallExpr *ast.GlobalCallExpr,
+	ctx *Context,
+) value.Literal {
+	date, isEmpty, err := e.globalCallExpectDateArg(
+		globalCallExpr,
+		ctx,
+		fail.ErrRelativeTimeWrongDate,
+	)
+	if err != nil {
+		return err
+	}
+
+	if isEmpty {
+		return &value.Str{Val: ""}
 	}
 
-	return &value.Str{Val: newFormat.Format(layout.Val)}
+	return &value.Str{Val: e.formatLocale().RelativeTime(date, timeNow())}
+}
+
+// globalCallExpectDateArg returns the date from the first argument, which
+// is a time, a date string or a Unix timestamp. The bool is true for empty
+// strings and zero times.
+func (e *Evaluator) globalCallExpectDateArg(
+	call *ast.GlobalCallExpr,
+	ctx *Context,
+	wrongDateErr string,
+) (time.Time, bool, *value.Error) {
+	val := e.evalLiteral(call.Arguments[0], ctx)
+	if isError(val) {
+		return time.Time{}, false, val.(*value.Error)
+	}
+
+	switch val := val.(type) {
+	case *value.Time:
+		return val.Val, val.Val.IsZero(), nil
+	case *value.Int:
+		return time.Unix(val.Val, 0).UTC(), false, nil
+	case *value.Str:
+		if val.Val == "" {
+			return time.Time{}, true, nil
+		}
+
+		parseLayout := getDateTimeLayout(val.Val)
+		if parseLayout == "" {
+			return time.Time{}, false, e.newError(call, ctx, wrongDateErr, val.Val)
+		}
+
+		date, parseErr := time.Parse(parseLayout, val.Val)
+		if parseErr != nil {
+			return time.Time{}, false, e.newError(
+				call,
+				ctx,
+				fail.ErrFormatDateParseErr,
+				val.Val,
+				parseLayout,
+			)
+		}
+
+		return date, false, nil
+	}
+
+	return time.Time{}, false, e.newError(
+		call,
+		ctx,
+		fail.ErrGlobalFuncWrongType,
+		call.Name,
+		value.STR_VAL,
+		1,
+		val.Type(),
+	)
+}
+
+func (e *Evaluator) globalFuncTranslate(
+	globalCallExpr *ast.GlobalCallExpr,
+	ctx *Context,
+) value.Literal {
+	key, err := e.globalCallExpectStrArg(globalCallExpr, ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	var params map[string]any
+	if len(globalCallExpr.Arguments) > 1 {
+		arg := e.evalLiteral(globalCallExpr.Arguments[1], ctx)
+		if isError(arg) {
+			return arg
+		}
+
+		obj, ok := arg.(*value.Obj)
+		if !ok {
+			return e.newError(
+				globalCallExpr,
+				ctx,
+				fail.ErrGlobalFuncWrongType,
+				globalCallExpr.Name,
+				value.OBJ_VAL,
+				2,
+				arg.Type(),
+			)
+		}
+
+		params = obj.Native().(map[string]any)
+	}
+
+	if e.config == nil || e.config.Translator == nil {
+		return &value.Str{Val: key.Val}
+	}
+
+	locale := e.currentLocale()
+	msg, ok := e.config.Translator.Translate(locale, key.Val, params)
+	if !ok {
+		e.config.Log().Warn("missing translation", "key", key.Val, "locale", locale,
+			"path", ctx.absPath, "line", globalCallExpr.Pos().Line())
+		return &value.Str{Val: key.Val}
+	}
+
+	return &value.Str{Val: msg}
+}
+
+// currentLocale returns the locale of the render call or the locale
+// from the config.
+func (e *Evaluator) currentLocale() string {
+	if e.locale == "" && e.config != nil {
+		return e.config.Locale
+	}
+	return e.locale
+}
+
+// formatLocale returns rules for formatting numbers and dates
+// in the current locale.
+func (e *Evaluator) formatLocale() *i18n.Locale {
+	return i18n.LookupLocale(e.currentLocale())
 }
 
 func (e *Evaluator) valuesToNativeType(args []value.Literal) []any {
@@ -1060,6 +1701,122 @@ func (e *Evaluator) valuesToNativeType(args []value.Literal) []any {
 	return vals
 }
 
+// globalFuncMinMax returns the smallest or the biggest number out of
+// the arguments. A single array argument is used as the list of numbers.
+func (e *Evaluator) globalFuncMinMax(
+	globalCallExpr *ast.GlobalCallExpr,
+	ctx *Context,
+	pick func([]value.Literal, func(a, b value.Literal) int) value.Literal,
+) value.Literal {
+	nums := make([]value.Literal, len(globalCallExpr.Arguments))
+	for i := range globalCallExpr.Arguments {
+		nums[i] = e.evalLiteral(globalCallExpr.Arguments[i], ctx)
+		if isError(nums[i]) {
+			return nums[i]
+		}
+	}
+
+	fromArr := false
+	if arr, ok := nums[0].(*value.Arr); ok && len(nums) == 1 {
+		nums, fromArr = arr.Elements, true
+	}
+
+	if len(nums) == 0 {
+		return NIL
+	}
+
+	for i, num := range nums {
+		if num.Type() == value.INT_VAL || num.Type() == value.FLOAT_VAL {
+			continue
+		}
+
+		argIdx := i + 1
+		if fromArr {
+			argIdx = 1
+		}
+
+		return e.newError(
+			globalCallExpr,
+			ctx,
+			fail.ErrGlobalFuncWrongType,
+			globalCallExpr.Name,
+			"number",
+			argIdx,
+			num.Type(),
+		)
+	}
+
+	return pick(nums, compareValues)
+}
+
+// globalFuncRange returns an array of integers from start up to, but not
+// including, end. With a single argument the range starts at 0.
+func (e *Evaluator) globalFuncRange(
+	globalCallExpr *ast.GlobalCallExpr,
+	ctx *Context,
+) value.Literal {
+	bounds := make([]int64, len(globalCallExpr.Arguments))
+	for i := range globalCallExpr.Arguments {
+		arg := e.evalLiteral(globalCallExpr.Arguments[i], ctx)
+		if isError(arg) {
+			return arg
+		}
+
+		num, ok := arg.(*value.Int)
+		if !ok {
+			return e.newError(
+				globalCallExpr,
+				ctx,
+				fail.ErrGlobalFuncWrongType,
+				globalCallExpr.Name,
+				value.INT_VAL,
+				i+1,
+				arg.Type(),
+			)
+		}
+
+		bounds[i] = num.Val
+	}
+
+	start, end, step := int64(0), bounds[0], int64(1)
+	if len(bounds) > 1 {
+		start, end = bounds[0], bounds[1]
+	}
+
+	if len(bounds) > 2 {
+		step = bounds[2]
+	}
+
+	if step == 0 {
+		return e.newError(globalCallExpr, ctx, fail.ErrRangeZeroStep)
+	}
+
+	count := rangeLen(start, end, step)
+	if count > maxRangeLen {
+		return e.newError(globalCallExpr, ctx, fail.ErrRangeTooLarge, maxRangeLen, count)
+	}
+
+	elems := make([]value.Literal, count)
+	for i := range elems {
+		elems[i] = &value.Int{Val: start + int64(i)*step}
+	}
+
+	return &value.Arr{Elements: elems}
+}
+
+// rangeLen returns the number of elements from start up to, but not
+// including, end with the given step. It doesn't overflow on big bounds.
+func rangeLen(start, end, step int64) uint64 {
+	switch {
+	case step > 0 && end > start:
+		return (uint64(end)-uint64(start)-1)/uint64(step) + 1
+	case step < 0 && end < start:
+		return (uint64(start)-uint64(end)-1)/uint64(-step) + 1
+	}
+
+	return 0
+}
+
 func (e *Evaluator) operatorExp(
 	op string,
 	left,
@@ -1068,7 +1825,11 @@ func (e *Evaluator) operatorExp(
 	ctx *Context,
 ) value.Literal {
 	if op == "==" || op == "!=" {
-		return e.comparrisonInfixExp(op, right, left, leftNode, ctx)
+		return e.comparrisonInfixExp(op, right, left)
+	}
+
+	if op == "~" {
+		return e.concatInfixExp(op, right, left, leftNode, ctx)
 	}
 
 	switch l := left.(type) {
@@ -1078,6 +1839,8 @@ func (e *Evaluator) operatorExp(
 		return e.floatInfixExp(op, right, l, leftNode, ctx)
 	case *value.Str:
 		return e.stringInfixExp(op, right, l, leftNode, ctx)
+	case *value.Time:
+		return e.timeInfixExp(op, right, l, leftNode, ctx)
 	}
 
 	return e.newError(leftNode, ctx, fail.ErrCannotUseOperator, op, left.Type(), op, right.Type())
@@ -1107,6 +1870,10 @@ func (e *Evaluator) intInfixExp(
 	leftNode ast.Node,
 	ctx *Context,
 ) value.Literal {
+	if _, ok := right.(*value.Float); ok && op != "%" {
+		return e.floatInfixExp(op, right, &value.Float{Val: float64(l.Val)}, leftNode, ctx)
+	}
+
 	r, ok := right.(*value.Int)
 	if !ok {
 		return e.newError(leftNode, ctx, fail.ErrCannotUseOperator, op, l.Type(), op, right.Type())
@@ -1125,6 +1892,9 @@ func (e *Evaluator) intInfixExp(
 		}
 		return &value.Int{Val: l.Val / r.Val}
 	case "%":
+		if r.Val == 0 {
+			return e.newError(leftNode, ctx, fail.ErrDivisionByZero)
+		}
 		return &value.Int{Val: l.Val % r.Val}
 	case ">":
 		return nativeBoolToBoolObj(l.Val > r.Val)
@@ -1139,40 +1909,72 @@ func (e *Evaluator) intInfixExp(
 	return e.newError(leftNode, ctx, fail.ErrCannotUseOperator, op, l.Type(), op, right.Type())
 }
 
+// comparrisonInfixExp compares values of any type. Integers and floats
+// are promoted to floats, while arrays and objects are compared deeply.
 func (e *Evaluator) comparrisonInfixExp(
 	op string, // == or !=
 	right,
 	left value.Literal,
+) value.Literal {
+	areEqual := valuesEqual(left, right)
+	if op == "!=" {
+		areEqual = !areEqual
+	}
+
+	return nativeBoolToBoolObj(areEqual)
+}
+
+// concatInfixExp joins two scalar values into a string.
+// Numbers and booleans are converted the same way they are printed.
+func (e *Evaluator) concatInfixExp(
+	op string, // ~
+	right,
+	left value.Literal,
 	leftNode ast.Node,
 	ctx *Context,
 ) value.Literal {
-	if left.Type() != right.Type() {
-		return nativeBoolToBoolObj(op == "!=")
+	leftStr, leftOk := concatOperand(left)
+	rightStr, rightOk := concatOperand(right)
+
+	if !leftOk || !rightOk {
+		return e.newError(
+			leftNode,
+			ctx,
+			fail.ErrCannotUseOperator,
+			op,
+			left.Type(),
+			op,
+			right.Type(),
+		)
 	}
-	var areEqual bool
 
-	switch l := left.(type) {
-	case *value.Int:
-		areEqual = l.Val == right.(*value.Int).Val
-	case *value.Float:
-		areEqual = l.Val == right.(*value.Float).Val
-	case *value.Str:
-		areEqual = l.Val == right.(*value.Str).Val
-	case *value.Bool:
-		areEqual = l.Val == right.(*value.Bool).Val
-	case *value.Arr, *value.Obj:
-		areEqual = reflect.DeepEqual(left, right)
-	case *value.Nil:
-		areEqual = true
-	default:
-		e.newError(leftNode, ctx, fail.ErrCannotUseOperator, op, l.Type(), op, right.Type())
+	return &value.Str{Val: leftStr + rightStr}
+}
+
+func (e *Evaluator) timeInfixExp(
+	op string,
+	right value.Literal,
+	l *value.Time,
+	leftNode ast.Node,
+	ctx *Context,
+) value.Literal {
+	r, ok := right.(*value.Time)
+	if !ok {
+		return e.newError(leftNode, ctx, fail.ErrCannotUseOperator, op, l.Type(), op, right.Type())
 	}
 
-	if op == "!=" {
-		areEqual = !areEqual
+	switch op {
+	case ">":
+		return nativeBoolToBoolObj(l.Val.After(r.Val))
+	case "<":
+		return nativeBoolToBoolObj(l.Val.Before(r.Val))
+	case ">=":
+		return nativeBoolToBoolObj(!l.Val.Before(r.Val))
+	case "<=":
+		return nativeBoolToBoolObj(!l.Val.After(r.Val))
 	}
 
-	return nativeBoolToBoolObj(areEqual)
+	return e.newError(leftNode, ctx, fail.ErrCannotUseOperator, op, l.Type(), op, right.Type())
 }
 
 func (e *Evaluator) stringInfixExp(
@@ -1202,6 +2004,10 @@ func (e *Evaluator) floatInfixExp(
 	ctx *Context,
 ) value.Literal {
 	r, ok := right.(*value.Float)
+	if i, isInt := right.(*value.Int); isInt {
+		r, ok = &value.Float{Val: float64(i.Val)}, true
+	}
+
 	if !ok {
 		return e.newError(leftNode, ctx, fail.ErrCannotUseOperator, op, l.Type(), op, right.Type())
 	}
@@ -1284,7 +2090,75 @@ func (e *Evaluator) globalCallExpectStrArg(
 
 func (e *Evaluator) newError(node ast.Node, ctx *Context, format string, a ...any) *value.Error {
 	return &value.Error{
-		Err:     fail.New(node.Pos(), ctx.absPath, fail.OriginEval, format, a...),
+		Err: e.withDetails(
+			fail.New(node.Pos(), ctx.absPath, fail.OriginEval, format, a...),
+			ctx,
+		),
 		ErrorID: format,
 	}
 }
+
+// pushFrame adds a frame to the template call stack and returns
+// a function that removes it.
+func (e *Evaluator) pushFrame(
+	kind fail.FrameKind,
+	name, absPath string,
+	pos *position.Pos,
+) func() {
+	e.frames = append(e.frames, fail.Frame{Kind: kind, Name: name, Filepath: absPath, Pos: pos})
+	return func() { e.frames = e.frames[:len(e.frames)-1] }
+}
+
+// withDetails attaches the template call stack to the failure. In DebugMode,
+// variables in scope are attached as well to show them on the error page.
+// They are encoded lazily, because many errors are discarded, like the
+// ones inside defined().
+func (e *Evaluator) withDetails(failure *fail.Error, ctx *Context) *fail.Error {
+	failure.WithStack(e.stackTrace())
+
+	if e.config == nil || !e.config.DebugMode {
+		return failure
+	}
+
+	scope := ctx.scope
+
+	return failure.WithVarsFunc(func() map[string]string {
+		vars := map[string]string{}
+		for name, val := range scope.Vars() {
+			if name == "global" {
+				continue
+			}
+
+			if encoded, err := val.JSON(); err == nil {
+				vars[name] = encoded
+			}
+		}
+
+		return vars
+	})
+}
+
+// stackTrace returns a copy of the template call stack with
+// the innermost frame first.
+func (e *Evaluator) stackTrace() []fail.Frame {
+	if len(e.frames) == 0 {
+		return nil
+	}
+
+	stack := slices.Clone(e.frames)
+	slices.Reverse(stack)
+
+	return stack
+}
+
+// wrapError returns an error that wraps err at the position of the node.
+// The code is kept when err was created with fail.Errorf.
+func (e *Evaluator) wrapError(node ast.Node, ctx *Context, err error) *value.Error {
+	return &value.Error{
+		Err: e.withDetails(
+			fail.FromError(err, node.Pos(), ctx.absPath, fail.OriginEval),
+			ctx,
+		),
+		ErrorID: "%s",
+	}
+}
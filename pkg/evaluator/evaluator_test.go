@@ -100,6 +100,19 @@ func TestEvalNumericExp(t *testing.T) {
 		{250, `{{ 2.0 + 1.2 }}`, "3.2"},
 		{260, `{{ 0.0 + 0.0 }}`, "0.0"},
 		{270, `{{ 0.0 }}`, "0.0"},
+		// Mixed ints and floats
+		{280, `{{ 3 + 2.0 }}`, "5.0"},
+		{290, `{{ 2.5 + 3 }}`, "5.5"},
+		{300, `{{ 5 - 1.5 }}`, "3.5"},
+		{310, `{{ 4.5 - 2 }}`, "2.5"},
+		{320, `{{ 3 * 2.5 }}`, "7.5"},
+		{330, `{{ 10 / 2.5 }}`, "4.0"},
+		{340, `{{ 7.5 / 3 }}`, "2.5"},
+		{350, `{{ 1 + 2.5 * 2 }}`, "6.0"},
+		{360, `{{ 3 > 2.5 }}`, "1"},
+		{370, `{{ 0.5 < 1 }}`, "1"},
+		{380, `{{ 2 >= 2.0 }}`, "1"},
+		{390, `{{ 2.1 <= 2 }}`, "0"},
 	}
 
 	for _, tc := range cases {
@@ -375,10 +388,17 @@ func TestEvalBooleanExp(t *testing.T) {
 		{2190, "{{ 1 == true }}", "0"},
 		{2200, "{{ false == 0 }}", "0"},
 		{2210, "{{ 0 == false }}", "0"},
-		{2220, "{{ 1.0 == 1 }}", "0"},
-		{2230, "{{ 1 == 1.0 }}", "0"},
+		{2220, "{{ 1.0 == 1 }}", "1"},
+		{2230, "{{ 1 == 1.0 }}", "1"},
 		{2240, "{{ {} == 1 }}", "0"},
 		{2250, "{{ [] == 1 }}", "0"},
+		// Numeric promotion in arrays and objects
+		{2260, "{{ [1, 2] == [1.0, 2.0] }}", "1"},
+		{2270, "{{ {x: 1} == {x: 1.0} }}", "1"},
+		{2280, "{{ {x: 1, y: 2} == {y: 2, x: 1} }}", "1"},
+		{2290, "{{ {x: 1} == {x: 1, y: nil} }}", "0"},
+		{2300, "{{ [1, '2'] == [1, 2] }}", "0"},
+		{2310, "{{ 0.5 != 1 }}", "1"},
 	}
 
 	for _, tc := range cases {
@@ -465,6 +485,19 @@ func TestEvalStringExp(t *testing.T) {
 		{310, `{{ "tab\there" }}`, "tab\there"},
 		// Long string concatenation
 		{320, `{{ "a" + "b" + "c" + "d" + "e" }}`, "abcde"},
+		// Concatenation with ~
+		{330, `{{ "Count: " ~ 5 }}`, "Count: 5"},
+		{340, `{{ 5 ~ " items" }}`, "5 items"},
+		{350, `{{ "Pi: " ~ 3.14 }}`, "Pi: 3.14"},
+		{360, `{{ "a" ~ "b" ~ "c" }}`, "abc"},
+		{370, `{{ 1 ~ 2 }}`, "12"},
+		{380, `{{ "Total: " ~ 2 + 3 }}`, "Total: 5"},
+		{390, `{{ "Total: " ~ 2 * 3 }}`, "Total: 6"},
+		{400, `{{ "Total: " ~ (2 + 3) }}`, "Total: 5"},
+		{410, `{{ "Active: " ~ true }}`, "Active: 1"},
+		{420, `{{ "Empty: " ~ nil }}`, "Empty: "},
+		{430, `{{ n = 3; "Step " ~ n ~ " of " ~ 5 }}`, "Step 3 of 5"},
+		{440, `{{ "<b>" ~ 1 }}`, "&lt;b&gt;1"},
 	}
 
 	for _, tc := range cases {
@@ -579,6 +612,9 @@ func TestEvalIfDir(t *testing.T) {
 		{420, `@if(5 - 3 == 1)Yes@end`, ""},
 		{430, `@if(2 - 2)No@elseYes@end`, "Yes"},
 		{440, `@if(-1 + 2)Yes@elseNo@end`, "Yes"},
+		{450, `@if('a' ~ 1 == 'a1')~ Yes @end`, "Yes"},
+		{460, `@if(2 == 2.0)Yes@end`, "Yes"},
+		{470, `@if(1.5 > 1)Yes@end`, "Yes"},
 		// Nested if statements
 		{
 			390,
@@ -828,6 +864,65 @@ func TestIncDecDir(t *testing.T) {
 	}
 }
 
+func TestEvalSwitchDir(t *testing.T) {
+	cases := []struct {
+		id     uint
+		inp    string
+		expect string
+	}{
+		{10, `@switch('paid')@case('paid')Paid@case('refunded')Refunded@end`, "Paid"},
+		{20, `@switch('refunded')@case('paid')Paid@case('refunded')Refunded@end`, "Refunded"},
+		{30, `@switch('new')@case('paid')Paid@end`, ""},
+		{40, `@switch('new')@case('paid')Paid@defaultOther@end`, "Other"},
+		{50, `@switch('new')@defaultOther@end`, "Other"},
+		// Multiple values per case
+		{60, `@switch('shipped')@case('paid', 'shipped')Sent@defaultOther@end`, "Sent"},
+		{70, `@switch(3)@case(1, 2)Low@case(3, 4)High@end`, "High"},
+		// No fallthrough to the next case
+		{80, `@switch(1)@case(1)One@case(1)Again@defaultOther@end`, "One"},
+		{90, `@switch(1)@case(1)@case(2)Two@end`, ""},
+		// Values are compared like with ==
+		{100, `@switch(2)@case(1)One@case(2.0)Two@end`, "Two"},
+		{110, `@switch('1')@case(1)Int@defaultStr@end`, "Str"},
+		{120, `@switch(nil)@case(nil)Nil@end`, "Nil"},
+		{130, `@switch([1, 2])@case([1, 2])Arr@end`, "Arr"},
+		{140, `{{ n = 5 }}@switch(n * 2)@case(10)Ten@end`, "Ten"},
+		// Expressions in cases and blocks
+		{150, `{{ a = 'x' }}@switch('xy')@case(a ~ 'y'){{ a }}@end`, "x"},
+		{160, `@switch(true)@case(1 > 2)A@case(2 > 1)B@end`, "B"},
+		{
+			170,
+			`
+				@switch('shipped')
+					@case('paid', 'shipped')
+						@if(true)On the way@end
+					@case('refunded')
+						Money returned
+					@default
+						Processing
+				@end
+			`,
+			"On the way",
+		},
+		{
+			180,
+			`@switch(1)@case(1)@switch(2)@case(2)Inner@end@defaultOuter@end`,
+			"Inner",
+		},
+	}
+
+	for _, tc := range cases {
+		evaluated, err := testEval(tc.inp)
+		if err != nil {
+			t.Errorf("Case: %d. Evaluation failed: %s", tc.id, err)
+		}
+
+		if res := strings.TrimSpace(evaluated.String()); res != tc.expect {
+			t.Errorf("Case: %d. Result is not %q, got %q", tc.id, tc.expect, res)
+		}
+	}
+}
+
 func TestEvalForDir(t *testing.T) {
 	cases := []struct {
 		id     uint
@@ -991,6 +1086,88 @@ func TestEvalObjLit(t *testing.T) {
 	}
 }
 
+func TestEvalLambdaExpr(t *testing.T) {
+	cases := []struct {
+		id     uint
+		inp    string
+		expect string
+	}{
+		{10, `{{ [1, 2, 3, 4].filter(n => n % 2 == 0) }}`, "2, 4"},
+		{
+			20,
+			`{{ [{n: 'a', on: true}, {n: 'b', on: false}].filter(u => u.on).map(u => u.n) }}`,
+			"a",
+		},
+		{30, `{{ [1, 2, 3].map((n, i) => n * i) }}`, "0, 2, 6"},
+		{40, `{{ min = 2; [1, 2, 3].filter(n => n >= min) }}`, "2, 3"},
+		{50, `{{ [3, 1, 2].sortBy(n => -n) }}`, "3, 2, 1"},
+		{
+			60,
+			`{{ [1, 2, 3, 4].groupBy(n => n % 2 == 0 ? 'even' : 'odd').json().raw() }}`,
+			`{"even":[2,4],"odd":[1,3]}`,
+		},
+		{70, `{{ [{p: 2}, {p: 3}].sum(i => i.p * 2) }}`, "10"},
+		{80, `{{ double = n => n * 2; [1, 2].map(double) }}`, "2, 4"},
+		{90, `{{ [[1, 2], [3]].map(a => a.map(n => n + 1).sum()) }}`, "5, 4"},
+		{100, `{{ [1, 2].map(() => 'x') }}`, "x, x"},
+		{110, `{{ [1].map((a, b, c) => c).json() }}`, "[null]"},
+		{120, `@each(n in [1, 2, 3].filter(n => n > 1)){{ n }}@end`, "23"},
+		{130, `{{ n = 5; [1].map(n => n + 1); n }}`, "25"},
+		{140, `{{ (n => n) }}`, ""},
+		// Parameters shadow outer variables of any type
+		{150, `{{ [[1, 2], [3]].map(x => x.map(x => x * 2)).json() }}`, "[[2,4],[6]]"},
+		{160, `{{ x = 'str'; [1, 2].map(x => x + 1) }}`, "2, 3"},
+		{170, `{{ x = 'str'; [1].map(x => x); x }}`, "1str"},
+		{180, `{{ [[1], [2]].map((x, i) => x.map((y, i) => i)).json() }}`, "[[0],[0]]"},
+	}
+
+	for _, tc := range cases {
+		evaluationExpected(t, tc.inp, tc.expect, tc.id)
+	}
+
+	t.Run("parameter shadows data variable", func(t *testing.T) {
+		data := map[string]any{
+			"name":  "Anna",
+			"users": []map[string]any{{"name": "Serhii"}, {"name": "Vova"}},
+		}
+
+		inp := `{{ users.map(name => name.name) }}, {{ name }}`
+		evaluated := testEvalWithData(t, inp, data, 190)
+
+		if res := evaluated.String(); res != "Serhii, Vova, Anna" {
+			t.Fatalf("Case: 190. Result is not %q, got %q", "Serhii, Vova, Anna", res)
+		}
+	})
+}
+
+func TestEvalLambdaExprError(t *testing.T) {
+	cases := []struct {
+		id   uint
+		inp  string
+		code fail.Code
+	}{
+		{10, `{{ [1].map(n => n.nope()) }}`, fail.CodeFuncNotDefined},
+		{20, `{{ [1].map(loop => 1) }}`, fail.CodeReservedIdentifiers},
+		{30, `{{ [1].sortBy(n => n + 'a') }}`, fail.CodeCannotUseOperator},
+	}
+
+	for _, tc := range cases {
+		evaluated, failure := testEval(tc.inp)
+		if failure != nil {
+			t.Fatalf("Case: %d. evaluation failed: %s", tc.id, failure)
+		}
+
+		errObj, ok := evaluated.(*value.Error)
+		if !ok {
+			t.Fatalf("Case: %d. expected error, got %q", tc.id, evaluated)
+		}
+
+		if errObj.Err.Code() != tc.code {
+			t.Fatalf("Case: %d. expected code %q, got %q", tc.id, tc.code, errObj.Err.Code())
+		}
+	}
+}
+
 func TestEvalComments(t *testing.T) {
 	cases := []struct {
 		id     uint
@@ -1052,15 +1229,6 @@ func TestCannotUseOperatorError(t *testing.T) {
 		op    string
 		right value.ValueType
 	}{
-		// Int + Float (both directions)
-		{10, "{{ 3 + 2.0 }}", value.INT_VAL, "+", value.FLOAT_VAL},
-		{20, "{{ 2.0 + 3 }}", value.FLOAT_VAL, "+", value.INT_VAL},
-		{30, "{{ 5 - 1.5 }}", value.INT_VAL, "-", value.FLOAT_VAL},
-		{40, "{{ 4.5 - 2 }}", value.FLOAT_VAL, "-", value.INT_VAL},
-		{50, "{{ 3 * 2.5 }}", value.INT_VAL, "*", value.FLOAT_VAL},
-		{60, "{{ 2.5 * 4 }}", value.FLOAT_VAL, "*", value.INT_VAL},
-		{70, "{{ 10 / 2.5 }}", value.INT_VAL, "/", value.FLOAT_VAL},
-		{80, "{{ 7.5 / 3 }}", value.FLOAT_VAL, "/", value.INT_VAL},
 		// Arithmetic with strings
 		{90, "{{ 5 * 'x' }}", value.INT_VAL, "*", value.STR_VAL},
 		{100, "{{ 'x' * 3 }}", value.STR_VAL, "*", value.INT_VAL},
@@ -1135,6 +1303,10 @@ func TestCannotUseOperatorError(t *testing.T) {
 		{640, "{{ nil - 1 }}", value.NIL_VAL, "-", value.INT_VAL},
 		{650, "{{ nil * 1 }}", value.NIL_VAL, "*", value.INT_VAL},
 		{660, "{{ nil / 1 }}", value.NIL_VAL, "/", value.INT_VAL},
+		// Concatenation with arrays and objects
+		{670, "{{ 'a' ~ [] }}", value.STR_VAL, "~", value.ARR_VAL},
+		{680, "{{ {} ~ 'a' }}", value.OBJ_VAL, "~", value.STR_VAL},
+		{690, "{{ 1 ~ {} }}", value.INT_VAL, "~", value.OBJ_VAL},
 	}
 
 	for _, tc := range cases {
@@ -1242,9 +1414,304 @@ func TestEvalFormatDateGlobalFunc(t *testing.T) {
 		{300, "{{ formatDate('00:00:00', '15:04:05') }}", "00:00:00"},
 		{310, "{{ formatDate('12:34:56', '2006-01-02 15:04:05') }}", "0000-01-01 12:34:56"},
 		{320, "{{ formatDate('23:59:59', '15:04:05') }}", "23:59:59"},
+		// Named formats
+		{330, "{{ formatDate('2026-03-05 14:02:03', 'short') }}", "03/05/2026"},
+		{340, "{{ formatDate('2026-03-05 14:02:03', 'long') }}", "March 5, 2026"},
+		{350, "{{ formatDate('2026-03-05 14:02:03', 'time') }}", "2:02 PM"},
+		{360, "{{ formatDate('2026-03-05 14:02:03', 'datetime') }}", "03/05/2026 2:02 PM"},
+		{370, "{{ formatDate('2026-03-05 14:02:03', 'iso') }}", "2026-03-05T14:02:03Z"},
+		// Time zones, RFC 3339 dates and Unix timestamps
+		{
+			380,
+			"{{ formatDate('2026-03-05 14:02:03', 'iso', 'Europe/Kyiv') }}",
+			"2026-03-05T16:02:03+02:00",
+		},
+		{390, "{{ formatDate('2026-03-05T14:02:03+02:00', 'iso') }}", "2026-03-05T14:02:03+02:00"},
+		{400, "{{ formatDate(0, '2006-01-02 15:04:05') }}", "1970-01-01 00:00:00"},
 	}
 
 	for _, tc := range cases {
 		evaluationExpected(t, tc.inp, tc.expect, tc.id)
 	}
 }
+
+func TestEvalMathGlobalFuncs(t *testing.T) {
+	cases := []struct {
+		id     uint
+		inp    string
+		expect string
+	}{
+		// min
+		{10, "{{ min(3, 1, 2) }}", "1"},
+		{20, "{{ min(3, 1.5, 2) }}", "1.5"},
+		{30, "{{ min(7) }}", "7"},
+		{40, "{{ min([4, 2, 8]) }}", "2"},
+		{50, "{{ min([]) }}", ""},
+		// max
+		{60, "{{ max(3, 1, 2) }}", "3"},
+		{70, "{{ max(3, 4.5, 2) }}", "4.5"},
+		{80, "{{ max([4, 2, 8]) }}", "8"},
+		{90, "{{ max(-1, -5) }}", "-1"},
+		// range
+		{100, "{{ range(5).join(',') }}", "0,1,2,3,4"},
+		{110, "{{ range(2, 5).join(',') }}", "2,3,4"},
+		{120, "{{ range(0, 10, 3).join(',') }}", "0,3,6,9"},
+		{130, "{{ range(5, 0, -2).join(',') }}", "5,3,1"},
+		{140, "{{ range(0).len() }}", "0"},
+		{150, "{{ range(5, 2).len() }}", "0"},
+		{160, "@each(i in range(1, 4)){{ i }}@end", "123"},
+		{170, "{{ range(0, 100000).len() }}", "100000"},
+		{
+			180,
+			"{{ range(9223372036854775800, 9223372036854775807, 5).join(',') }}",
+			"9223372036854775800,9223372036854775805",
+		},
+	}
+
+	for _, tc := range cases {
+		evaluationExpected(t, tc.inp, tc.expect, tc.id)
+	}
+}
+
+func TestEvalMathGlobalFuncsError(t *testing.T) {
+	cases := []struct {
+		id  uint
+		inp string
+		err *fail.Error
+	}{
+		{
+			10,
+			"{{ min(1, 'a') }}",
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrGlobalFuncWrongType,
+				"min",
+				"number",
+				2,
+				value.STR_VAL,
+			),
+		},
+		{
+			20,
+			"{{ max([1, nil]) }}",
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrGlobalFuncWrongType,
+				"max",
+				"number",
+				1,
+				value.NIL_VAL,
+			),
+		},
+		{
+			30,
+			"{{ range(1.5) }}",
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrGlobalFuncWrongType,
+				"range",
+				value.INT_VAL,
+				1,
+				value.FLOAT_VAL,
+			),
+		},
+		{
+			40,
+			"{{ range(0, 5, 0) }}",
+			fail.New(nil, "/path/to/file", fail.OriginEval, fail.ErrRangeZeroStep),
+		},
+		{
+			50,
+			"{{ 5 % 0 }}",
+			fail.New(nil, "/path/to/file", fail.OriginEval, fail.ErrDivisionByZero),
+		},
+		{
+			60,
+			"{{ range(0, 1000000000000) }}",
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrRangeTooLarge,
+				maxRangeLen,
+				1000000000000,
+			),
+		},
+	}
+
+	for _, tc := range cases {
+		evaluated, failure := testEval(tc.inp)
+		if failure != nil {
+			t.Fatalf("Case: %d. evaluation failed: %s", tc.id, failure)
+		}
+
+		err, ok := evaluated.(*value.Error)
+		if !ok {
+			t.Fatalf("Case: %d. evaluation must fail, got %q", tc.id, evaluated.String())
+		}
+
+		if err.String() != tc.err.String() {
+			t.Fatalf("Case: %d. Error message must be:\n%q\ngot:\n%q", tc.id, tc.err, err)
+		}
+	}
+}
+
+func TestEvalUndefinedModes(t *testing.T) {
+	cases := []struct {
+		id     uint
+		inp    string
+		conf   *config.Config
+		expect string
+		warns  int
+	}{
+		{10, "<b>{{ name }}</b>", &config.Config{Undefined: config.UndefinedEmpty}, "<b></b>", 1},
+		{
+			20,
+			"{{ name }}",
+			&config.Config{Undefined: config.UndefinedPlaceholder, UndefinedPlaceholder: "?%s?"},
+			"?name?",
+			1,
+		},
+		{30, "{{ defined(name) }}", &config.Config{Undefined: config.UndefinedEmpty}, "0", 0},
+		{40, "{{ hasValue(name) }}", &config.Config{Undefined: config.UndefinedEmpty}, "0", 0},
+		{50, "{{ name ? 1 : 2 }}", &config.Config{Undefined: config.UndefinedEmpty}, "2", 1},
+		{60, "{{ {}.age }}", &config.Config{}, "", 0},
+		{
+			70,
+			"{{ {a: 1}.b }}",
+			&config.Config{Undefined: config.UndefinedPlaceholder, StrictKeys: true},
+			"[undefined b]",
+			1,
+		},
+		{
+			80,
+			"{{ {a: 1}['b'] }}",
+			&config.Config{Undefined: config.UndefinedEmpty, StrictKeys: true},
+			"",
+			1,
+		},
+		{90, "{{ defined({a: 1}.b) }}", &config.Config{StrictKeys: true}, "0", 0},
+		{100, "{{ {A: 1}.a }}", &config.Config{StrictKeys: true}, "1", 0},
+		{
+			110,
+			"<b>{{ user.name }}</b>",
+			&config.Config{Undefined: config.UndefinedEmpty},
+			"<b></b>",
+			1,
+		},
+		{
+			120,
+			"{{ user.address.city }}",
+			&config.Config{Undefined: config.UndefinedPlaceholder},
+			"[undefined user.address.city]",
+			1,
+		},
+		{130, "{{ items[0] }}", &config.Config{Undefined: config.UndefinedEmpty}, "", 1},
+		{
+			140,
+			"{{ user['name'] }}",
+			&config.Config{Undefined: config.UndefinedPlaceholder, UndefinedPlaceholder: "?%s?"},
+			"?user[&#34;name&#34;]?",
+			1,
+		},
+		{
+			150,
+			"{{ user = nil; user.name }}",
+			&config.Config{Undefined: config.UndefinedEmpty},
+			"",
+			1,
+		},
+		{
+			160,
+			"{{ items = nil; items[1] }}",
+			&config.Config{Undefined: config.UndefinedPlaceholder},
+			"[undefined items[1]]",
+			1,
+		},
+		{170, "{{ defined(user.name) }}", &config.Config{Undefined: config.UndefinedEmpty}, "0", 0},
+		{180, "{{ user.name ? 1 : 2 }}", &config.Config{Undefined: config.UndefinedEmpty}, "2", 1},
+	}
+
+	for _, tc := range cases {
+		warns := 0
+		conf := config.New()
+		tc.conf.OnUndefined = func(*fail.Error) { warns++ }
+		conf.Configure(tc.conf)
+
+		evaluated, failure := testEvalWithConfig(tc.inp, conf)
+		if failure != nil {
+			t.Fatalf("Case: %d. evaluation failed: %s", tc.id, failure)
+		}
+
+		if errObj, ok := evaluated.(*value.Error); ok {
+			t.Fatalf("Case: %d. evaluation failed: %s", tc.id, errObj)
+		}
+
+		if res := evaluated.String(); res != tc.expect {
+			t.Fatalf("Case: %d. Result is not '%s', got '%s'", tc.id, tc.expect, res)
+		}
+
+		if warns != tc.warns {
+			t.Fatalf("Case: %d. Expected %d warnings, got %d", tc.id, tc.warns, warns)
+		}
+	}
+}
+
+func TestEvalStrictKeysError(t *testing.T) {
+	conf := config.New()
+	conf.Configure(&config.Config{StrictKeys: true})
+
+	evaluated, failure := testEvalWithConfig("{{ {name: 'Anna'}.nme }}", conf)
+	if failure != nil {
+		t.Fatalf("evaluation failed: %s", failure)
+	}
+
+	errObj, ok := evaluated.(*value.Error)
+	if !ok {
+		t.Fatalf("expected error, got %q", evaluated)
+	}
+
+	if errObj.Err.Code() != fail.CodeObjKeyIsUndefined {
+		t.Fatalf("expected code %q, got %q", fail.CodeObjKeyIsUndefined, errObj.Err.Code())
+	}
+}
+
+func TestErrorVarsInDebugMode(t *testing.T) {
+	conf := config.New()
+	conf.Configure(&config.Config{DebugMode: true})
+
+	evaluated, failure := testEvalWithConfig(`{{ x = [1, 2]; x = []; missing }}`, conf)
+	if failure != nil {
+		t.Fatalf("evaluation failed: %s", failure)
+	}
+
+	errObj, ok := evaluated.(*value.Error)
+	if !ok {
+		t.Fatalf("expected an error, got %s", evaluated)
+	}
+
+	if vars := errObj.Err.Vars(); len(vars) != 1 || vars["x"] != "[]" {
+		t.Fatalf("expected x to be in variables, got %v", vars)
+	}
+}
+
+func testEvalWithConfig(inp string, conf *config.Config) (value.Value, *fail.Error) {
+	l := lexer.New(inp)
+	p := parser.New(l, file.New("file", "to/file", "/path/to/file", nil))
+	prog := p.ParseProgram()
+
+	if p.HasErrors() {
+		return nil, p.Errors()[0]
+	}
+
+	e := New(&config.Func{}, conf)
+	ctx := NewContext(value.NewScope(), prog.AbsPath)
+
+	return e.Eval(prog, ctx), nil
+}
@@ -3,6 +3,7 @@ package evaluator
 import (
 	"math"
 
+	"github.com/textwire/textwire/v4/pkg/fail"
 	"github.com/textwire/textwire/v4/pkg/utils"
 	"github.com/textwire/textwire/v4/pkg/value"
 )
@@ -41,8 +42,18 @@ func floatFloorFunc(receiver value.Literal, _ ...value.Literal) (value.Literal,
 	return &value.Int{Val: int64(math.Floor(val))}, nil
 }
 
-// floatRoundFunc returns the rounded value of a float to the nearest integer
-func floatRoundFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+// floatRoundFunc returns the rounded value of a float to the nearest integer.
+// With a precision argument it returns a float rounded to that many decimals
+func floatRoundFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	val := receiver.(*value.Float).Val
-	return &value.Int{Val: int64(math.Round(val))}, nil
+	if len(args) == 0 {
+		return &value.Int{Val: int64(math.Round(val))}, nil
+	}
+
+	precision, ok := args[0].(*value.Int)
+	if !ok {
+		return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.FLOAT_VAL, "round")
+	}
+
+	return &value.Float{Val: roundTo(val, precision.Val)}, nil
 }
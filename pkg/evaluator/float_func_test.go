@@ -58,6 +58,36 @@ func TestEvalFloatFunctions(t *testing.T) {
 		{420, `{{ -1.1.round() }}`, "-1"},
 		{430, `{{ -1.9.round() }}`, "-2"},
 		{440, `{{ -5.125.round() }}`, "-5"},
+		{450, `{{ 5.125.round(2) }}`, "5.13"},
+		{460, `{{ 3.14159.round(3) }}`, "3.142"},
+		{470, `{{ 2.5.round(0) }}`, "3.0"},
+		{480, `{{ 1234.5.round(-2) }}`, "1200.0"},
+		// clamp
+		{490, `{{ 5.5.clamp(1, 10) }}`, "5.5"},
+		{500, `{{ 15.5.clamp(1, 10) }}`, "10"},
+		{510, `{{ 0.5.clamp(1.5, 10) }}`, "1.5"},
+		{520, `{{ 0.5.clamp(10, 1) }}`, "1"},
+		// pow
+		{530, `{{ 2.0.pow(3) }}`, "8.0"},
+		{540, `{{ 1.5.pow(2) }}`, "2.25"},
+		// sqrt
+		{550, `{{ 6.25.sqrt() }}`, "2.5"},
+		// min, max
+		{560, `{{ 2.5.min(3, 1.5) }}`, "1.5"},
+		{570, `{{ 2.5.min(1) }}`, "1"},
+		{580, `{{ 2.5.max(3, 1.5) }}`, "3"},
+		{590, `{{ 2.5.max(1) }}`, "2.5"},
+		// percent
+		{600, `{{ 12.5.percent(50) }}`, "25.0"},
+		{610, `{{ 0.5.percent(2.0) }}`, "25.0"},
+		// between
+		{620, `{{ 2.5.between(1, 3) }}`, "1"},
+		{630, `{{ 2.5.between(3, 1) }}`, "1"},
+		{640, `{{ 3.0.between(1, 3) }}`, "1"},
+		{650, `{{ 3.5.between(1, 3) }}`, "0"},
+		{660, `{{ 1.5.round(400) }}`, "1.5"},
+		{670, `{{ 1234.5.round(-400) }}`, "0.0"},
+		{680, `{{ 10.0.pow(300).round(15) == 10.0.pow(300) }}`, "1"},
 	}
 
 	for _, tc := range cases {
@@ -24,6 +24,21 @@ var functions = map[value.ValueType]map[string]*value.Builtin{
 		"last":       {Fn: strLastFunc},
 		"repeat":     {Fn: strRepeatFunc},
 		"format":     {Fn: strFormatFunc},
+		"replace":    {Fn: strReplaceFunc},
+		"replaceAll": {Fn: strReplaceAllFunc},
+		"startsWith": {Fn: strStartsWithFunc},
+		"endsWith":   {Fn: strEndsWithFunc},
+		"indexOf":    {Fn: strIndexOfFunc},
+		"slug":       {Fn: strSlugFunc},
+		"padLeft":    {Fn: strPadLeftFunc},
+		"padRight":   {Fn: strPadRightFunc},
+		"wordwrap":   {Fn: strWordwrapFunc},
+		"stripTags":  {Fn: strStripTagsFunc},
+		"urlEncode":  {Fn: strURLEncodeFunc},
+		"base64":     {Fn: strBase64Func},
+		"sha256":     {Fn: strSha256Func},
+		"match":      {Fn: strMatchFunc},
+		"test":       {Fn: strTestFunc},
 	},
 	value.ARR_VAL: {
 		"len":      {Fn: arrLenFunc},
@@ -35,15 +50,38 @@ var functions = map[value.ValueType]map[string]*value.Builtin{
 		"contains": {Fn: arrContainsFunc},
 		"append":   {Fn: arrAppendFunc},
 		"prepend":  {Fn: arrPrependFunc},
+		"sort":     {Fn: arrSortFunc},
+		"sortBy":   {Fn: arrSortByFunc},
+		"unique":   {Fn: arrUniqueFunc},
+		"filter":   {Fn: arrFilterFunc},
+		"map":      {Fn: arrMapFunc},
+		"groupBy":  {Fn: arrGroupByFunc},
+		"chunk":    {Fn: arrChunkFunc},
+		"first":    {Fn: arrFirstFunc},
+		"last":     {Fn: arrLastFunc},
+		"sum":      {Fn: arrSumFunc},
+		"avg":      {Fn: arrAvgFunc},
+		"min":      {Fn: arrMinFunc},
+		"max":      {Fn: arrMaxFunc},
+		"pluck":    {Fn: arrPluckFunc},
+		"indexOf":  {Fn: arrIndexOfFunc},
+		"flatten":  {Fn: arrFlattenFunc},
 		"json":     {Fn: jsonFunc},
 	},
 	value.FLOAT_VAL: {
-		"int":   {Fn: floatIntFunc},
-		"str":   {Fn: floatStrFunc},
-		"abs":   {Fn: floatAbsFunc},
-		"ceil":  {Fn: floatCeilFunc},
-		"floor": {Fn: floatFloorFunc},
-		"round": {Fn: floatRoundFunc},
+		"int":     {Fn: floatIntFunc},
+		"str":     {Fn: floatStrFunc},
+		"abs":     {Fn: floatAbsFunc},
+		"ceil":    {Fn: floatCeilFunc},
+		"floor":   {Fn: floatFloorFunc},
+		"round":   {Fn: floatRoundFunc},
+		"clamp":   {Fn: numClampFunc},
+		"pow":     {Fn: numPowFunc},
+		"sqrt":    {Fn: numSqrtFunc},
+		"min":     {Fn: numMinFunc},
+		"max":     {Fn: numMaxFunc},
+		"percent": {Fn: numPercentFunc},
+		"between": {Fn: numBetweenFunc},
 	},
 	value.INT_VAL: {
 		"float":   {Fn: intFloatFunc},
@@ -51,18 +89,59 @@ var functions = map[value.ValueType]map[string]*value.Builtin{
 		"str":     {Fn: intStrFunc},
 		"len":     {Fn: intLenFunc},
 		"decimal": {Fn: intDecimalFunc},
+		"round":   {Fn: intRoundFunc},
+		"clamp":   {Fn: numClampFunc},
+		"pow":     {Fn: numPowFunc},
+		"sqrt":    {Fn: numSqrtFunc},
+		"min":     {Fn: numMinFunc},
+		"max":     {Fn: numMaxFunc},
+		"percent": {Fn: numPercentFunc},
+		"between": {Fn: numBetweenFunc},
+		"isEven":  {Fn: intIsEvenFunc},
+		"isOdd":   {Fn: intIsOddFunc},
 	},
 	value.BOOL_VAL: {
 		"binary": {Fn: boolBinaryFunc},
 		"then":   {Fn: boolThenFunc},
 	},
+	value.TIME_VAL: {
+		"year":   {Fn: timeYearFunc},
+		"month":  {Fn: timeMonthFunc},
+		"day":    {Fn: timeDayFunc},
+		"hour":   {Fn: timeHourFunc},
+		"minute": {Fn: timeMinuteFunc},
+		"second": {Fn: timeSecondFunc},
+		"unix":   {Fn: timeUnixFunc},
+		"add":    {Fn: timeAddFunc},
+		"before": {Fn: timeBeforeFunc},
+		"after":  {Fn: timeAfterFunc},
+		"tz":     {Fn: timeTzFunc},
+		"json":   {Fn: jsonFunc},
+	},
 	value.OBJ_VAL: {
-		"json":  {Fn: jsonFunc},
-		"camel": {Fn: objCamelFunc},
-		"get":   {Fn: objGetFunc},
+		"json":    {Fn: jsonFunc},
+		"camel":   {Fn: objCamelFunc},
+		"get":     {Fn: objGetFunc},
+		"keys":    {Fn: objKeysFunc},
+		"values":  {Fn: objValuesFunc},
+		"entries": {Fn: objEntriesFunc},
+		"len":     {Fn: objLenFunc},
+		"has":     {Fn: objHasFunc},
+		"merge":   {Fn: objMergeFunc},
+		"pick":    {Fn: objPickFunc},
+		"omit":    {Fn: objOmitFunc},
 	},
 }
 
+// IsBuiltinFunc reports whether the function with the given name is
+// built-in for the type. Built-in functions can't be redefined.
+func IsBuiltinFunc(t value.ValueType, name string) bool {
+	_, builtin := functions[t][name]
+	_, locale := localeFunctions[t][name]
+	_, output := outputFunctions[t][name]
+	return builtin || locale || output
+}
+
 // jsonFunc convert value to json representation
 func jsonFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
 	json, err := receiver.JSON()
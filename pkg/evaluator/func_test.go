@@ -1015,6 +1015,350 @@ func TestFunctionGivesError(t *testing.T) {
 				1,
 			),
 		},
+		{
+			830,
+			`{{ [1, 2].sortBy() }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncMissingArg,
+				value.ARR_VAL,
+				"sortBy",
+			),
+		},
+		{
+			840,
+			`{{ [1, 2].sortBy(1) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncFirstArgKey,
+				value.ARR_VAL,
+				"sortBy",
+			),
+		},
+		{
+			850,
+			`{{ [1, 2].chunk(0) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncPositiveArg,
+				value.ARR_VAL,
+				"chunk",
+			),
+		},
+		{
+			860,
+			`{{ [1, 2].chunk('2') }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncFirstArgInt,
+				value.ARR_VAL,
+				"chunk",
+			),
+		},
+		{
+			870,
+			`{{ [1, '2'].sum() }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncNumericElems,
+				value.ARR_VAL,
+				"sum",
+				value.STR_VAL,
+			),
+		},
+		{
+			880,
+			`{{ [{p: nil}].avg('p') }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncNumericElems,
+				value.ARR_VAL,
+				"avg",
+				value.NIL_VAL,
+			),
+		},
+		{
+			890,
+			`{{ [1, 2].indexOf() }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncMissingArg,
+				value.ARR_VAL,
+				"indexOf",
+			),
+		},
+		{
+			900,
+			`{{ [1, 2].flatten('1') }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncFirstArgInt,
+				value.ARR_VAL,
+				"flatten",
+			),
+		},
+		{
+			910,
+			`{{ {}.has() }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncMissingArg,
+				value.OBJ_VAL,
+				"has",
+			),
+		},
+		{
+			920,
+			`{{ {}.has(1) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncFirstArgStr,
+				value.OBJ_VAL,
+				"has",
+			),
+		},
+		{
+			930,
+			`{{ {}.merge() }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncMissingArg,
+				value.OBJ_VAL,
+				"merge",
+			),
+		},
+		{
+			940,
+			`{{ {}.merge({}, [1]) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncArgObj,
+				2,
+				value.OBJ_VAL,
+				"merge",
+			),
+		},
+		{
+			950,
+			`{{ {}.pick('a', 2) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncArgStr,
+				2,
+				value.OBJ_VAL,
+				"pick",
+			),
+		},
+		{
+			960,
+			`{{ {}.omit() }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncMissingArg,
+				value.OBJ_VAL,
+				"omit",
+			),
+		},
+		{
+			970,
+			`{{ "a".replace("a") }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncMissingArg,
+				value.STR_VAL,
+				"replace",
+			),
+		},
+		{
+			980,
+			`{{ "a".replaceAll("a", 1) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncSecondArgStr,
+				value.STR_VAL,
+				"replaceAll",
+			),
+		},
+		{
+			990,
+			`{{ "a".startsWith(1) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncFirstArgStr,
+				value.STR_VAL,
+				"startsWith",
+			),
+		},
+		{
+			1000,
+			`{{ "a".padLeft("3") }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncFirstArgInt,
+				value.STR_VAL,
+				"padLeft",
+			),
+		},
+		{
+			1010,
+			`{{ "a".wordwrap() }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncMissingArg,
+				value.STR_VAL,
+				"wordwrap",
+			),
+		},
+		{
+			1020,
+			`{{ "a".test("[a-") }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncRegex,
+				value.STR_VAL,
+				"test",
+				"[a-",
+			),
+		},
+		{
+			1030,
+			`{{ 5.clamp(1) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncMissingArg,
+				value.INT_VAL,
+				"clamp",
+			),
+		},
+		{
+			1040,
+			`{{ 5.clamp(1, '10') }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncArgNum,
+				2,
+				value.INT_VAL,
+				"clamp",
+			),
+		},
+		{
+			1050,
+			`{{ 2.5.pow('2') }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncArgNum,
+				1,
+				value.FLOAT_VAL,
+				"pow",
+			),
+		},
+		{
+			1060,
+			`{{ 5.max(1, nil) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncArgNum,
+				2,
+				value.INT_VAL,
+				"max",
+			),
+		},
+		{
+			1070,
+			`{{ 5.percent(0) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrDivisionByZero,
+			),
+		},
+		{
+			1080,
+			`{{ 1.5.round('2') }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncFirstArgInt,
+				value.FLOAT_VAL,
+				"round",
+			),
+		},
+		{
+			1090,
+			`{{ 15.round(1.5) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncFirstArgInt,
+				value.INT_VAL,
+				"round",
+			),
+		},
+		{
+			1100,
+			`{{ "a".padLeft(9000000000000000000) }}`,
+			fail.New(
+				nil,
+				"/path/to/file",
+				fail.OriginEval,
+				fail.ErrFuncStrTooLong,
+				value.STR_VAL,
+				"padLeft",
+				100_000,
+				9000000000000000000,
+			),
+		},
 	}
 
 	for _, tc := range cases {
@@ -3,6 +3,7 @@ package evaluator
 import (
 	"strconv"
 
+	"github.com/textwire/textwire/v4/pkg/fail"
 	"github.com/textwire/textwire/v4/pkg/value"
 )
 
@@ -50,3 +51,41 @@ func intDecimalFunc(receiver value.Literal, args ...value.Literal) (value.Litera
 
 	return &value.Str{Val: formatIntDecimals(val, separator, decimals)}, nil
 }
+
+// intRoundFunc rounds an integer with a negative precision to tens,
+// hundreds and so on (e.g., 1234.round(-2) → 1200)
+func intRoundFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if len(args) == 0 {
+		return receiver, nil
+	}
+
+	precision, ok := args[0].(*value.Int)
+	if !ok {
+		return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.INT_VAL, "round")
+	}
+
+	if precision.Val >= 0 {
+		return receiver, nil
+	}
+
+	// int64 has 19 digits, any of them rounds to 0 past 10^18
+	if precision.Val < -18 {
+		return &value.Int{Val: 0}, nil
+	}
+
+	val := receiver.(*value.Int).Val
+
+	return &value.Int{Val: int64(roundTo(float64(val), precision.Val))}, nil
+}
+
+// intIsEvenFunc checks if an integer is even
+func intIsEvenFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	val := receiver.(*value.Int).Val
+	return nativeBoolToBoolObj(val%2 == 0), nil
+}
+
+// intIsOddFunc checks if an integer is odd
+func intIsOddFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	val := receiver.(*value.Int).Val
+	return nativeBoolToBoolObj(val%2 != 0), nil
+}
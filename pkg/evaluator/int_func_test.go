@@ -42,6 +42,56 @@ func TestEvalIntFunctions(t *testing.T) {
 		{270, `{{ 100.decimal('|', 1) }}`, "100|0"},
 		{280, `{{ (-100).decimal('|', 0) }}`, "-100"},
 		{290, `{{ 1.decimal(',') }}`, "1,00"},
+		// round
+		{300, `{{ 1234.round() }}`, "1234"},
+		{310, `{{ 1234.round(2) }}`, "1234"},
+		{320, `{{ 1234.round(-1) }}`, "1230"},
+		{330, `{{ 1250.round(-2) }}`, "1300"},
+		{340, `{{ 1234.round(-3) }}`, "1000"},
+		{350, `{{ (-1250).round(-2) }}`, "-1300"},
+		// clamp
+		{360, `{{ 5.clamp(1, 10) }}`, "5"},
+		{370, `{{ 15.clamp(1, 10) }}`, "10"},
+		{380, `{{ 0.clamp(1, 10) }}`, "1"},
+		{390, `{{ 15.clamp(10, 1) }}`, "10"},
+		{400, `{{ 15.clamp(1, 9.5) }}`, "9.5"},
+		// pow
+		{410, `{{ 2.pow(10) }}`, "1024"},
+		{420, `{{ 5.pow(0) }}`, "1"},
+		{430, `{{ 2.pow(-1) }}`, "0.5"},
+		{440, `{{ 4.pow(0.5) }}`, "2.0"},
+		// sqrt
+		{450, `{{ 16.sqrt() }}`, "4.0"},
+		{460, `{{ 2.sqrt().round(3) }}`, "1.414"},
+		// min, max
+		{470, `{{ 5.min(3, 8) }}`, "3"},
+		{480, `{{ 5.min(8) }}`, "5"},
+		{490, `{{ 5.min(4.5) }}`, "4.5"},
+		{500, `{{ 5.max(3, 8) }}`, "8"},
+		{510, `{{ 5.max(3) }}`, "5"},
+		// percent
+		{520, `{{ 25.percent(200) }}`, "12.5"},
+		{530, `{{ 50.percent(50) }}`, "100.0"},
+		// between
+		{540, `{{ 5.between(1, 10) }}`, "1"},
+		{550, `{{ 5.between(10, 1) }}`, "1"},
+		{560, `{{ 10.between(1, 10) }}`, "1"},
+		{570, `{{ 11.between(1, 10) }}`, "0"},
+		{580, `{{ 5.between(4.5, 5.5) }}`, "1"},
+		// isEven, isOdd
+		{590, `{{ 4.isEven() }}`, "1"},
+		{600, `{{ 3.isEven() }}`, "0"},
+		{610, `{{ 0.isEven() }}`, "1"},
+		{620, `{{ (-3).isOdd() }}`, "1"},
+		{630, `{{ 4.isOdd() }}`, "0"},
+		// pow with big exponents
+		{640, `{{ 1.pow(9000000000000000000) }}`, "1"},
+		{650, `{{ (-1).pow(9000000000000000001) }}`, "-1"},
+		{660, `{{ 2.pow(62) }}`, "4611686018427387904"},
+		{670, `{{ 2.pow(64) }}`, "18446744073709552000.0"},
+		{680, `{{ 10.pow(19) > 10.pow(18) }}`, "1"},
+		{690, `{{ 1234.round(-400) }}`, "0"},
+		{700, `{{ 1234.round(-19) }}`, "0"},
 	}
 
 	for _, tc := range cases {
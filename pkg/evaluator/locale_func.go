@@ -0,0 +1,93 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/i18n"
+	"github.com/textwire/textwire/v4/pkg/value"
+)
+
+// localeFunc is a built-in function that formats its receiver
+// with the rules of the locale of the render call.
+type localeFunc func(
+	locale *i18n.Locale,
+	receiver value.Literal,
+	args ...value.Literal,
+) (value.Literal, error)
+
+var localeFunctions = map[value.ValueType]map[string]localeFunc{
+	value.INT_VAL: {
+		"format":   numFormatFunc,
+		"currency": numCurrencyFunc,
+	},
+	value.FLOAT_VAL: {
+		"format":   numFormatFunc,
+		"currency": numCurrencyFunc,
+	},
+	value.TIME_VAL: {
+		"format": timeFormatFunc,
+	},
+}
+
+// numFormatFunc formats a number with group separators of the locale.
+// The optional argument is the count of decimals.
+func numFormatFunc(
+	locale *i18n.Locale,
+	receiver value.Literal,
+	args ...value.Literal,
+) (value.Literal, error) {
+	decimals := -1
+	if receiver.Is(value.INT_VAL) {
+		decimals = 0
+	}
+
+	if len(args) > 1 {
+		return nil, fail.Errorf(fail.ErrFuncMaxArgs, receiver.Type(), "format", 1)
+	}
+
+	if len(args) == 1 {
+		decimalsArg, ok := args[0].(*value.Int)
+		if !ok {
+			return nil, fail.Errorf(fail.ErrFuncFirstArgInt, receiver.Type(), "format")
+		}
+		decimals = max(int(decimalsArg.Val), 0)
+	}
+
+	// Integers don't go through float64, it can't hold all of their digits
+	if num, ok := receiver.(*value.Int); ok {
+		out := locale.FormatInt(num.Val)
+		if decimals > 0 {
+			out += locale.Decimal + strings.Repeat("0", decimals)
+		}
+		return &value.Str{Val: out}, nil
+	}
+
+	return &value.Str{Val: locale.FormatNumber(toFloat(receiver), decimals)}, nil
+}
+
+// numCurrencyFunc formats a number as an amount in the currency
+// with the given ISO 4217 code, like "EUR".
+func numCurrencyFunc(
+	locale *i18n.Locale,
+	receiver value.Literal,
+	args ...value.Literal,
+) (value.Literal, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, receiver.Type(), "currency")
+	}
+
+	code, ok := args[0].(*value.Str)
+	if !ok {
+		return nil, fail.Errorf(fail.ErrFuncFirstArgStr, receiver.Type(), "currency")
+	}
+
+	return &value.Str{Val: locale.FormatCurrency(toFloat(receiver), code.Val)}, nil
+}
+
+func toFloat(num value.Literal) float64 {
+	if i, ok := num.(*value.Int); ok {
+		return float64(i.Val)
+	}
+	return num.(*value.Float).Val
+}
@@ -0,0 +1,66 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/value"
+)
+
+func TestEvalLocaleFunctions(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	cases := []struct {
+		id     uint
+		inp    string
+		locale string
+		expect string
+	}{
+		// format
+		{10, `{{ 1234567.format() }}`, "", "1,234,567"},
+		{20, `{{ (-1234).format() }}`, "en", "-1,234"},
+		{30, `{{ 1234.5.format() }}`, "en", "1,234.5"},
+		{40, `{{ 1234.5.format(2) }}`, "de", "1.234,50"},
+		{50, `{{ 1234.format(1) }}`, "uk-UA", "1\u00a0234,0"},
+		{60, `{{ 999.format() }}`, "xx", "999"},
+		// currency
+		{70, `{{ 1234.5.currency('EUR') }}`, "en", "€1,234.50"},
+		{80, `{{ 1234.5.currency('eur') }}`, "de", "1.234,50\u00a0€"},
+		{90, `{{ (-5).currency('USD') }}`, "en", "-$5.00"},
+		{100, `{{ 1500.currency('JPY') }}`, "en", "¥1,500"},
+		{110, `{{ 10.currency('XYZ') }}`, "en", "XYZ10.00"},
+		// formatDate
+		{120, `{{ formatDate('2026-03-05', 'long') }}`, "uk", "5 березня 2026 р."},
+		{130, `{{ formatDate('2026-03-05', 'short') }}`, "de", "05.03.2026"},
+		{140, `{{ formatDate('2026-03-05', '2 {month}') }}`, "es", "5 marzo"},
+		// relativeTime
+		{150, `{{ relativeTime('2026-03-05 11:57:00') }}`, "en", "3 minutes ago"},
+		{160, `{{ relativeTime('2026-03-05 11:59:55') }}`, "en", "just now"},
+		{170, `{{ relativeTime('2026-03-07 12:00:00') }}`, "en", "in 2 days"},
+		{180, `{{ relativeTime('2026-03-05 11:00:00') }}`, "en", "1 hour ago"},
+		{190, `{{ relativeTime('2026-03-05 11:57:00') }}`, "uk", "3 хвилини тому"},
+		{200, `{{ relativeTime('2026-02-28 12:00:00') }}`, "pl", "5 dni temu"},
+		{210, `{{ relativeTime('2024-03-05 12:00:00') }}`, "de", "vor 2 Jahren"},
+		{220, `{{ relativeTime('') }}`, "en", ""},
+		{230, `{{ 9007199254740993.format() }}`, "en", "9,007,199,254,740,993"},
+		{240, `{{ 9223372036854775807.format(2) }}`, "de", "9.223.372.036.854.775.807,00"},
+	}
+
+	for _, tc := range cases {
+		evaluated, failure := testEvalWithConfig(tc.inp, &config.Config{Locale: tc.locale})
+		if failure != nil {
+			t.Fatalf("Case: %d. evaluation failed: %s", tc.id, failure)
+		}
+
+		if errObj, ok := evaluated.(*value.Error); ok {
+			t.Fatalf("Case: %d. evaluation failed: %s", tc.id, errObj)
+		}
+
+		if res := evaluated.String(); res != tc.expect {
+			t.Fatalf("Case: %d. Result is not %q, got %q", tc.id, tc.expect, res)
+		}
+	}
+}
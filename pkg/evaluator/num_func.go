@@ -0,0 +1,189 @@
+package evaluator
+
+import (
+	"math"
+	"slices"
+
+	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/value"
+)
+
+// Functions in this file are shared by integers and floats
+
+// numClampFunc limits a number to the given range. Returns the number
+// itself or the bound that was reached
+func numClampFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if err := numArgs("clamp", receiver, args, 2); err != nil {
+		return nil, err
+	}
+
+	low, high := args[0], args[1]
+	if compareValues(low, high) > 0 {
+		low, high = high, low
+	}
+
+	if compareValues(receiver, low) < 0 {
+		return low, nil
+	}
+
+	if compareValues(receiver, high) > 0 {
+		return high, nil
+	}
+
+	return receiver, nil
+}
+
+// numPowFunc raises a number to the given power. Returns an integer when
+// both numbers are integers, the exponent isn't negative and the result
+// fits into an integer. Otherwise, returns a float
+func numPowFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if err := numArgs("pow", receiver, args, 1); err != nil {
+		return nil, err
+	}
+
+	base, isInt := receiver.(*value.Int)
+	exp, isIntExp := args[0].(*value.Int)
+
+	if isInt && isIntExp && exp.Val >= 0 {
+		if res, ok := intPow(base.Val, exp.Val); ok {
+			return &value.Int{Val: res}, nil
+		}
+	}
+
+	return &value.Float{Val: math.Pow(toFloat(receiver), toFloat(args[0]))}, nil
+}
+
+// numSqrtFunc returns the square root of a number as a float
+func numSqrtFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Float{Val: math.Sqrt(toFloat(receiver))}, nil
+}
+
+// numMinFunc returns the smallest number out of the receiver and
+// the given arguments
+func numMinFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if err := numArgs("min", receiver, args, 1); err != nil {
+		return nil, err
+	}
+
+	return slices.MinFunc(append([]value.Literal{receiver}, args...), compareValues), nil
+}
+
+// numMaxFunc returns the biggest number out of the receiver and
+// the given arguments
+func numMaxFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if err := numArgs("max", receiver, args, 1); err != nil {
+		return nil, err
+	}
+
+	return slices.MaxFunc(append([]value.Literal{receiver}, args...), compareValues), nil
+}
+
+// numPercentFunc returns what percent the number is of the given number
+func numPercentFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if err := numArgs("percent", receiver, args, 1); err != nil {
+		return nil, err
+	}
+
+	of := toFloat(args[0])
+	if of == 0 {
+		return nil, fail.Errorf(fail.ErrDivisionByZero)
+	}
+
+	return &value.Float{Val: toFloat(receiver) / of * 100}, nil
+}
+
+// numBetweenFunc checks if a number is within the given range. Both bounds
+// are inclusive and can be passed in any order
+func numBetweenFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if err := numArgs("between", receiver, args, 2); err != nil {
+		return nil, err
+	}
+
+	num, low, high := toFloat(receiver), toFloat(args[0]), toFloat(args[1])
+	if low > high {
+		low, high = high, low
+	}
+
+	return nativeBoolToBoolObj(num >= low && num <= high), nil
+}
+
+// numArgs makes sure that a number function got at least the given
+// amount of arguments and that every argument is a number
+func numArgs(funcName string, receiver value.Literal, args []value.Literal, minArgs int) error {
+	if len(args) < minArgs {
+		return fail.Errorf(fail.ErrFuncMissingArg, receiver.Type(), funcName)
+	}
+
+	for i, arg := range args {
+		if arg.Type() != value.INT_VAL && arg.Type() != value.FLOAT_VAL {
+			return fail.Errorf(fail.ErrFuncArgNum, i+1, receiver.Type(), funcName)
+		}
+	}
+
+	return nil
+}
+
+// intPow raises base to the exponent by squaring. The bool is false
+// when the result overflows an integer
+func intPow(base, exp int64) (int64, bool) {
+	res := int64(1)
+
+	for exp > 0 {
+		var ok bool
+
+		if exp&1 == 1 {
+			if res, ok = mulInt(res, base); !ok {
+				return 0, false
+			}
+		}
+
+		exp >>= 1
+		if exp == 0 {
+			break
+		}
+
+		if base, ok = mulInt(base, base); !ok {
+			return 0, false
+		}
+	}
+
+	return res, true
+}
+
+// mulInt multiplies two integers. The bool is false on overflow
+func mulInt(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	res := a * b
+	if res/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	return res, true
+}
+
+// roundTo rounds a number to the given amount of decimal places.
+// A negative precision rounds to tens, hundreds and so on
+func roundTo(num float64, precision int64) float64 {
+	// float64 doesn't hold more than 15 significant decimals
+	if precision > 15 {
+		return num
+	}
+
+	if precision < 0 {
+		pow := math.Pow(10, float64(-precision))
+		if math.IsInf(pow, 0) {
+			return 0
+		}
+		return math.Round(num/pow) * pow
+	}
+
+	pow := math.Pow(10, float64(precision))
+	if math.IsInf(num*pow, 0) {
+		return num
+	}
+
+	return math.Round(num*pow) / pow
+}
@@ -1,8 +1,8 @@
 package evaluator
 
 import (
-	"errors"
-	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/textwire/textwire/v4/pkg/fail"
@@ -19,22 +19,19 @@ func objGetFunc(receiver value.Literal, args ...value.Literal) (value.Literal, e
 	obj := receiver.(*value.Obj)
 
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.OBJ_VAL, "get")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.OBJ_VAL, "get")
 	}
 
 	if len(args) > 1 {
-		msg := fmt.Sprintf(fail.ErrFuncMaxArgs, value.OBJ_VAL, "get", 1)
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMaxArgs, value.OBJ_VAL, "get", 1)
 	}
 
 	pattern, ok := args[0].(*value.Str)
 	if !ok {
-		msg := fmt.Sprintf(fail.ErrFuncFirstArgStr, value.OBJ_VAL, "get")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.OBJ_VAL, "get")
 	}
 
-	patternStr := pattern.String()
+	patternStr := pattern.Val
 
 	if result, ok := obj.Pairs[patternStr]; ok {
 		return result, nil
@@ -68,3 +65,165 @@ func findObjKey(props []string, pairs map[string]value.Literal) value.Literal {
 
 	return NIL
 }
+
+// objKeysFunc returns the sorted keys of the object
+func objKeysFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	keys := slices.Sorted(maps.Keys(receiver.(*value.Obj).Pairs))
+
+	elems := make([]value.Literal, len(keys))
+	for i, key := range keys {
+		elems[i] = &value.Str{Val: key}
+	}
+
+	return &value.Arr{Elements: elems}, nil
+}
+
+// objValuesFunc returns the values of the object sorted by their keys
+func objValuesFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	pairs := receiver.(*value.Obj).Pairs
+	keys := slices.Sorted(maps.Keys(pairs))
+
+	elems := make([]value.Literal, len(keys))
+	for i, key := range keys {
+		elems[i] = pairs[key]
+	}
+
+	return &value.Arr{Elements: elems}, nil
+}
+
+// objEntriesFunc returns the pairs of the object sorted by their keys as
+// objects with the "key" and "value" fields
+func objEntriesFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	pairs := receiver.(*value.Obj).Pairs
+	keys := slices.Sorted(maps.Keys(pairs))
+
+	elems := make([]value.Literal, len(keys))
+	for i, key := range keys {
+		elems[i] = value.NewObj(map[string]value.Literal{
+			"key":   &value.Str{Val: key},
+			"value": pairs[key],
+		})
+	}
+
+	return &value.Arr{Elements: elems}, nil
+}
+
+// objLenFunc returns the number of keys in the object
+func objLenFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Int{Val: int64(len(receiver.(*value.Obj).Pairs))}, nil
+}
+
+// objHasFunc checks if the object has the given key. Nested keys are
+// separated with dots, like "user.name"
+func objHasFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.OBJ_VAL, "has")
+	}
+
+	key, ok := args[0].(*value.Str)
+	if !ok {
+		return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.OBJ_VAL, "has")
+	}
+
+	pairs := receiver.(*value.Obj).Pairs
+	if _, ok := pairs[key.Val]; ok {
+		return TRUE, nil
+	}
+
+	props := strings.Split(key.Val, ".")
+	for i, prop := range props {
+		val, ok := pairs[prop]
+		if !ok {
+			return FALSE, nil
+		}
+
+		if i == len(props)-1 {
+			break
+		}
+
+		obj, ok := val.(*value.Obj)
+		if !ok {
+			return FALSE, nil
+		}
+
+		pairs = obj.Pairs
+	}
+
+	return TRUE, nil
+}
+
+// objMergeFunc returns a new object with the keys of the object and the
+// given objects. Keys of the later objects override earlier ones
+func objMergeFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.OBJ_VAL, "merge")
+	}
+
+	merged := maps.Clone(receiver.(*value.Obj).Pairs)
+	if merged == nil {
+		merged = map[string]value.Literal{}
+	}
+
+	for i, arg := range args {
+		obj, ok := arg.(*value.Obj)
+		if !ok {
+			return nil, fail.Errorf(fail.ErrFuncArgObj, i+1, value.OBJ_VAL, "merge")
+		}
+
+		maps.Copy(merged, obj.Pairs)
+	}
+
+	return value.NewObj(merged), nil
+}
+
+// objPickFunc returns a new object with only the given keys
+func objPickFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	keys, err := objKeyArgs("pick", args)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := receiver.(*value.Obj).Pairs
+	picked := make(map[string]value.Literal, len(keys))
+
+	for _, key := range keys {
+		if val, ok := pairs[key]; ok {
+			picked[key] = val
+		}
+	}
+
+	return value.NewObj(picked), nil
+}
+
+// objOmitFunc returns a new object without the given keys
+func objOmitFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	keys, err := objKeyArgs("omit", args)
+	if err != nil {
+		return nil, err
+	}
+
+	omitted := maps.Clone(receiver.(*value.Obj).Pairs)
+	for _, key := range keys {
+		delete(omitted, key)
+	}
+
+	return value.NewObj(omitted), nil
+}
+
+func objKeyArgs(funcName string, args []value.Literal) ([]string, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.OBJ_VAL, funcName)
+	}
+
+	keys := make([]string, len(args))
+
+	for i, arg := range args {
+		key, ok := arg.(*value.Str)
+		if !ok {
+			return nil, fail.Errorf(fail.ErrFuncArgStr, i+1, value.OBJ_VAL, funcName)
+		}
+		keys[i] = key.Val
+	}
+
+	return keys, nil
+}
@@ -247,3 +247,60 @@ func TestObjGet(t *testing.T) {
 		evaluationExpected(t, tc.inp, tc.expect, tc.id)
 	}
 }
+
+func TestObjFunctions(t *testing.T) {
+	cases := []struct {
+		id     uint
+		inp    string
+		expect string
+	}{
+		// keys
+		{10, `{{ {b: 1, a: 2}.keys() }}`, "a, b"},
+		{20, `{{ {}.keys().len() }}`, "0"},
+		// values
+		{30, `{{ {b: 1, a: 2}.values() }}`, "2, 1"},
+		{40, `{{ {a: [1, 2], b: 'x'}.values().json().raw() }}`, `[[1,2],"x"]`},
+		// entries
+		{
+			50,
+			`@each(e in {b: 1, a: 2}.entries()){{ e.key }}={{ e.value }};@end`,
+			"a=2;b=1;",
+		},
+		{60, `{{ {}.entries().len() }}`, "0"},
+		// len
+		{70, `{{ {a: 1, b: 2, c: 3}.len() }}`, "3"},
+		{80, `{{ {}.len() }}`, "0"},
+		// has
+		{90, `{{ {a: 1}.has('a') }}`, "1"},
+		{100, `{{ {a: 1}.has('b') }}`, "0"},
+		{110, `{{ {a: nil}.has('a') }}`, "1"},
+		{120, `{{ {user: {name: 'Anna'}}.has('user.name') }}`, "1"},
+		{130, `{{ {user: {name: 'Anna'}}.has('user.age') }}`, "0"},
+		{140, `{{ {user: 'Anna'}.has('user.name') }}`, "0"},
+		{150, `{{ {'user.name': 1}.has('user.name') }}`, "1"},
+		// merge
+		{
+			160,
+			`{{ {size: 'md', color: 'gray'}.merge({color: 'red'}).json().raw() }}`,
+			`{"color":"red","size":"md"}`,
+		},
+		{170, `{{ {a: 1}.merge({b: 2}, {a: 3}).json().raw() }}`, `{"a":3,"b":2}`},
+		{180, `{{ d = {a: 1}; m = d.merge({a: 2}); d.a }}`, "1"},
+		{190, `{{ {}.merge({}).len() }}`, "0"},
+		// pick
+		{200, `{{ {a: 1, b: 2, c: 3}.pick('a', 'c').json().raw() }}`, `{"a":1,"c":3}`},
+		{210, `{{ {a: 1}.pick('z').len() }}`, "0"},
+		// omit
+		{
+			220,
+			`{{ {name: 'Anna', password: 'secret'}.omit('password').json().raw() }}`,
+			`{"name":"Anna"}`,
+		},
+		{230, `{{ o = {a: 1, b: 2}; x = o.omit('a', 'z'); o.len() }}`, "2"},
+		{240, `{{ {a: 1, b: 2}.omit('a', 'b').json() }}`, "{}"},
+	}
+
+	for _, tc := range cases {
+		evaluationExpected(t, tc.inp, tc.expect, tc.id)
+	}
+}
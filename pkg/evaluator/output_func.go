@@ -0,0 +1,45 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/value"
+)
+
+// outputFunc is a built-in function that depends on the output mode
+// of the page that is being rendered.
+type outputFunc func(
+	mode config.OutputMode,
+	receiver value.Literal,
+	args ...value.Literal,
+) (value.Literal, error)
+
+var outputFunctions = map[value.ValueType]map[string]outputFunc{
+	value.STR_VAL: {
+		"nl2br": strNl2brFunc,
+	},
+}
+
+// strNl2brFunc escapes the string and inserts <br> before line breaks.
+// The result is raw, so the <br> tags are not escaped again. In other
+// output modes than HTML the string is returned as it is.
+func strNl2brFunc(
+	mode config.OutputMode,
+	receiver value.Literal,
+	_ ...value.Literal,
+) (value.Literal, error) {
+	str := receiver.(*value.Str)
+	if mode != config.OutputHTML {
+		return str, nil
+	}
+
+	val := str.Val
+	if !str.IsRaw {
+		val = value.EscapeHTML(val)
+	}
+
+	replacer := strings.NewReplacer("\r\n", "<br>\r\n", "\n", "<br>\n")
+
+	return &value.Str{Val: replacer.Replace(val), IsRaw: true}, nil
+}
@@ -0,0 +1,67 @@
+package evaluator
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+)
+
+// regexLRU is a concurrency-safe cache of compiled regular expressions
+// that evicts the least recently used pattern when it's full
+type regexLRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type regexEntry struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func newRegexLRU(size int) *regexLRU {
+	return &regexLRU{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (c *regexLRU) get(pattern string) (*regexp.Regexp, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[pattern]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return el.Value.(*regexEntry).re, true
+}
+
+func (c *regexLRU) add(pattern string, re *regexp.Regexp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[pattern] = c.order.PushFront(&regexEntry{pattern: pattern, re: re})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*regexEntry).pattern)
+	}
+}
+
+func (c *regexLRU) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -1,9 +1,13 @@
 package evaluator
 
 import (
-	"errors"
-	"fmt"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"regexp"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/textwire/textwire/v4/pkg/fail"
@@ -12,6 +16,14 @@ import (
 
 const defaultCharTrim = "\t \n\r"
 
+// maxPadLen limits the length of strings made by padLeft() and
+// padRight(), so that a template can't allocate huge strings
+const maxPadLen = 100_000
+
+// regexCache keeps compiled patterns of match() and test() because
+// templates call them with the same patterns on every render
+var regexCache = newRegexLRU(500)
+
 // strLenFunc returns the length of the given string
 func strLenFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
 	val := receiver.(*value.Str).Val
@@ -26,8 +38,7 @@ func strSplitFunc(receiver value.Literal, args ...value.Literal) (value.Literal,
 	if len(args) > 0 {
 		str, ok := args[0].(*value.Str)
 		if !ok {
-			msg := fmt.Sprintf(fail.ErrFuncFirstArgStr, value.STR_VAL, "split")
-			return nil, errors.New(msg)
+			return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.STR_VAL, "split")
 		}
 
 		separator = str.Val
@@ -58,8 +69,7 @@ func strTrimFunc(receiver value.Literal, args ...value.Literal) (value.Literal,
 	if len(args) > 0 {
 		str, ok := args[0].(*value.Str)
 		if !ok {
-			msg := fmt.Sprintf(fail.ErrFuncFirstArgStr, value.STR_VAL, "trim")
-			return nil, errors.New(msg)
+			return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.STR_VAL, "trim")
 		}
 
 		chars = str.Val
@@ -112,14 +122,12 @@ func strReverseFunc(receiver value.Literal, _ ...value.Literal) (value.Literal,
 // strContainsFunc returns true if the string contains the given substring, false otherwise
 func strContainsFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.STR_VAL, "contains")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.STR_VAL, "contains")
 	}
 
 	firstArg, ok := args[0].(*value.Str)
 	if !ok {
-		msg := fmt.Sprintf(fail.ErrFuncFirstArgStr, value.STR_VAL, "contains")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.STR_VAL, "contains")
 	}
 
 	val := receiver.(*value.Str).Val
@@ -132,15 +140,13 @@ func strContainsFunc(receiver value.Literal, args ...value.Literal) (value.Liter
 func strTruncateFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	// Validate that at least the limit argument is provided
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.STR_VAL, "truncate")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.STR_VAL, "truncate")
 	}
 
 	// Validate that the first argument is an integer (the limit)
 	firstArg, ok := args[0].(*value.Int)
 	if !ok {
-		msg := fmt.Sprintf(fail.ErrFuncFirstArgInt, value.STR_VAL, "truncate")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.STR_VAL, "truncate")
 	}
 
 	val := receiver.(*value.Str).Val
@@ -163,8 +169,7 @@ func strTruncateFunc(receiver value.Literal, args ...value.Literal) (value.Liter
 		if ok {
 			ellipsis = secondArg.Val
 		} else {
-			msg := fmt.Sprintf(fail.ErrFuncSecondArgStr, value.STR_VAL, "truncate")
-			return nil, errors.New(msg)
+			return nil, fail.Errorf(fail.ErrFuncSecondArgStr, value.STR_VAL, "truncate")
 		}
 	}
 
@@ -200,8 +205,7 @@ func strAtFunc(receiver value.Literal, args ...value.Literal) (value.Literal, er
 	if len(args) != 0 {
 		firstArg, ok := args[0].(*value.Int)
 		if !ok {
-			msg := fmt.Sprintf(fail.ErrFuncFirstArgInt, value.STR_VAL, "at")
-			return nil, errors.New(msg)
+			return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.STR_VAL, "at")
 		}
 
 		index = int(firstArg.Val)
@@ -241,8 +245,7 @@ func strTrimRightFunc(receiver value.Literal, args ...value.Literal) (value.Lite
 	if len(args) > 0 {
 		str, ok := args[0].(*value.Str)
 		if !ok {
-			msg := fmt.Sprintf(fail.ErrFuncFirstArgStr, value.STR_VAL, "trimRight")
-			return nil, errors.New(msg)
+			return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.STR_VAL, "trimRight")
 		}
 
 		chars = str.Val
@@ -260,8 +263,7 @@ func strTrimLeftFunc(receiver value.Literal, args ...value.Literal) (value.Liter
 		str, ok := args[0].(*value.Str)
 
 		if !ok {
-			msg := fmt.Sprintf(fail.ErrFuncFirstArgStr, value.STR_VAL, "trimLeft")
-			return nil, errors.New(msg)
+			return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.STR_VAL, "trimLeft")
 		}
 
 		chars = str.Val
@@ -275,14 +277,12 @@ func strTrimLeftFunc(receiver value.Literal, args ...value.Literal) (value.Liter
 // strRepeatFunc returns a string repeated n times
 func strRepeatFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.STR_VAL, "repeat")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.STR_VAL, "repeat")
 	}
 
 	firstArg, ok := args[0].(*value.Int)
 	if !ok {
-		msg := fmt.Sprintf(fail.ErrFuncFirstArgInt, value.STR_VAL, "repeat")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.STR_VAL, "repeat")
 	}
 
 	val := receiver.(*value.Str).Val
@@ -300,8 +300,7 @@ func strRepeatFunc(receiver value.Literal, args ...value.Literal) (value.Literal
 // strFormatFunc embeds values into a string. Similar to sprintf in C.
 func strFormatFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
 	if len(args) == 0 {
-		msg := fmt.Sprintf(fail.ErrFuncMissingArg, value.STR_VAL, "format")
-		return nil, errors.New(msg)
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.STR_VAL, "format")
 	}
 
 	str := receiver.(*value.Str).Val
@@ -330,3 +329,360 @@ func strFormatFunc(receiver value.Literal, args ...value.Literal) (value.Literal
 
 	return &value.Str{Val: out.String()}, nil
 }
+
+// strReplaceFunc replaces the first occurrence of the substring
+func strReplaceFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	old, replacement, err := replaceArgs("replace", args)
+	if err != nil {
+		return nil, err
+	}
+
+	val := receiver.(*value.Str).Val
+
+	return &value.Str{Val: strings.Replace(val, old, replacement, 1)}, nil
+}
+
+// strReplaceAllFunc replaces all occurrences of the substring
+func strReplaceAllFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	old, replacement, err := replaceArgs("replaceAll", args)
+	if err != nil {
+		return nil, err
+	}
+
+	val := receiver.(*value.Str).Val
+
+	return &value.Str{Val: strings.ReplaceAll(val, old, replacement)}, nil
+}
+
+func replaceArgs(funcName string, args []value.Literal) (string, string, error) {
+	if len(args) < 2 {
+		return "", "", fail.Errorf(fail.ErrFuncMissingArg, value.STR_VAL, funcName)
+	}
+
+	old, ok := args[0].(*value.Str)
+	if !ok {
+		return "", "", fail.Errorf(fail.ErrFuncFirstArgStr, value.STR_VAL, funcName)
+	}
+
+	replacement, ok := args[1].(*value.Str)
+	if !ok {
+		return "", "", fail.Errorf(fail.ErrFuncSecondArgStr, value.STR_VAL, funcName)
+	}
+
+	return old.Val, replacement.Val, nil
+}
+
+// strStartsWithFunc returns true if the string starts with the prefix
+func strStartsWithFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	prefix, err := strArg("startsWith", args)
+	if err != nil {
+		return nil, err
+	}
+
+	val := receiver.(*value.Str).Val
+
+	return nativeBoolToBoolObj(strings.HasPrefix(val, prefix)), nil
+}
+
+// strEndsWithFunc returns true if the string ends with the suffix
+func strEndsWithFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	suffix, err := strArg("endsWith", args)
+	if err != nil {
+		return nil, err
+	}
+
+	val := receiver.(*value.Str).Val
+
+	return nativeBoolToBoolObj(strings.HasSuffix(val, suffix)), nil
+}
+
+// strIndexOfFunc returns the index of the first character of the
+// substring, or -1 when the string doesn't contain it. The index counts
+// characters, not bytes
+func strIndexOfFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	substr, err := strArg("indexOf", args)
+	if err != nil {
+		return nil, err
+	}
+
+	val := receiver.(*value.Str).Val
+
+	idx := strings.Index(val, substr)
+	if idx == -1 {
+		return &value.Int{Val: -1}, nil
+	}
+
+	return &value.Int{Val: int64(utf8.RuneCountInString(val[:idx]))}, nil
+}
+
+// strSlugFunc converts the string into a URL slug, like "hello-world".
+// Letters of any language and digits are kept, everything else is
+// replaced with the separator, which is "-" by default
+func strSlugFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	separator := "-"
+
+	if len(args) > 0 {
+		str, ok := args[0].(*value.Str)
+		if !ok {
+			return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.STR_VAL, "slug")
+		}
+
+		separator = str.Val
+	}
+
+	val := receiver.(*value.Str).Val
+
+	var out strings.Builder
+	out.Grow(len(val))
+
+	needSeparator := false
+
+	for _, r := range strings.ToLower(val) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			needSeparator = out.Len() > 0
+			continue
+		}
+
+		if needSeparator {
+			out.WriteString(separator)
+			needSeparator = false
+		}
+
+		out.WriteRune(r)
+	}
+
+	return &value.Str{Val: out.String()}, nil
+}
+
+// strPadLeftFunc pads the string from the left to the given length
+func strPadLeftFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	padding, err := padArgs("padLeft", receiver, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value.Str{Val: padding + receiver.(*value.Str).Val}, nil
+}
+
+// strPadRightFunc pads the string from the right to the given length
+func strPadRightFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	padding, err := padArgs("padRight", receiver, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value.Str{Val: receiver.(*value.Str).Val + padding}, nil
+}
+
+// padArgs returns the padding that makes the string as long as the
+// first argument. The padding repeats the second argument, a space by
+// default, and is cut to fit
+func padArgs(funcName string, receiver value.Literal, args []value.Literal) (string, error) {
+	if len(args) == 0 {
+		return "", fail.Errorf(fail.ErrFuncMissingArg, value.STR_VAL, funcName)
+	}
+
+	length, ok := args[0].(*value.Int)
+	if !ok {
+		return "", fail.Errorf(fail.ErrFuncFirstArgInt, value.STR_VAL, funcName)
+	}
+
+	pad := " "
+
+	if len(args) > 1 {
+		str, ok := args[1].(*value.Str)
+		if !ok {
+			return "", fail.Errorf(fail.ErrFuncSecondArgStr, value.STR_VAL, funcName)
+		}
+
+		pad = str.Val
+	}
+
+	if length.Val > maxPadLen {
+		return "", fail.Errorf(
+			fail.ErrFuncStrTooLong,
+			value.STR_VAL,
+			funcName,
+			maxPadLen,
+			length.Val,
+		)
+	}
+
+	missing := int(length.Val) - utf8.RuneCountInString(receiver.(*value.Str).Val)
+	if missing <= 0 || pad == "" {
+		return "", nil
+	}
+
+	padRunes := []rune(pad)
+	padding := make([]rune, missing)
+
+	for i := range padding {
+		padding[i] = padRunes[i%len(padRunes)]
+	}
+
+	return string(padding), nil
+}
+
+// strWordwrapFunc wraps the string at the given number of characters.
+// Lines are broken between words, long words are never split. The
+// second argument is the line break, "\n" by default
+func strWordwrapFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.STR_VAL, "wordwrap")
+	}
+
+	width, ok := args[0].(*value.Int)
+	if !ok {
+		return nil, fail.Errorf(fail.ErrFuncFirstArgInt, value.STR_VAL, "wordwrap")
+	}
+
+	lineBreak := "\n"
+
+	if len(args) > 1 {
+		str, ok := args[1].(*value.Str)
+		if !ok {
+			return nil, fail.Errorf(fail.ErrFuncSecondArgStr, value.STR_VAL, "wordwrap")
+		}
+
+		lineBreak = str.Val
+	}
+
+	lines := strings.Split(receiver.(*value.Str).Val, "\n")
+
+	for i, line := range lines {
+		lines[i] = wrapLine(line, int(width.Val), lineBreak)
+	}
+
+	return &value.Str{Val: strings.Join(lines, lineBreak)}, nil
+}
+
+func wrapLine(line string, width int, lineBreak string) string {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return line
+	}
+
+	var out strings.Builder
+	out.Grow(len(line))
+
+	lineLen := 0
+
+	for i, word := range words {
+		wordLen := utf8.RuneCountInString(word)
+
+		if i > 0 && lineLen+1+wordLen > width {
+			out.WriteString(lineBreak)
+			lineLen = 0
+		} else if i > 0 {
+			out.WriteByte(' ')
+			lineLen++
+		}
+
+		out.WriteString(word)
+		lineLen += wordLen
+	}
+
+	return out.String()
+}
+
+// strStripTagsFunc removes HTML tags from the string
+func strStripTagsFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	val := receiver.(*value.Str).Val
+
+	var out strings.Builder
+	out.Grow(len(val))
+
+	inTag := false
+
+	for _, r := range val {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			out.WriteRune(r)
+		}
+	}
+
+	return &value.Str{Val: out.String()}, nil
+}
+
+// strURLEncodeFunc escapes the string to be used in a URL query
+func strURLEncodeFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	val := receiver.(*value.Str).Val
+	return &value.Str{Val: url.QueryEscape(val)}, nil
+}
+
+// strBase64Func encodes the string with standard base64 encoding
+func strBase64Func(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	val := receiver.(*value.Str).Val
+	return &value.Str{Val: base64.StdEncoding.EncodeToString([]byte(val))}, nil
+}
+
+// strSha256Func returns the hex encoded SHA-256 hash of the string
+func strSha256Func(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	sum := sha256.Sum256([]byte(receiver.(*value.Str).Val))
+	return &value.Str{Val: hex.EncodeToString(sum[:])}, nil
+}
+
+// strMatchFunc returns the first match of the regular expression followed
+// by its capture groups, or an empty array when nothing matches
+func strMatchFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	re, err := regexArg("match", args)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := re.FindStringSubmatch(receiver.(*value.Str).Val)
+
+	elems := make([]value.Literal, len(matches))
+	for i, match := range matches {
+		elems[i] = &value.Str{Val: match}
+	}
+
+	return &value.Arr{Elements: elems}, nil
+}
+
+// strTestFunc returns true if the string matches the regular expression
+func strTestFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	re, err := regexArg("test", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return nativeBoolToBoolObj(re.MatchString(receiver.(*value.Str).Val)), nil
+}
+
+func regexArg(funcName string, args []value.Literal) (*regexp.Regexp, error) {
+	pattern, err := strArg(funcName, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if re, ok := regexCache.get(pattern); ok {
+		return re, nil
+	}
+
+	re, compileErr := regexp.Compile(pattern)
+	if compileErr != nil {
+		return nil, fail.Errorf(fail.ErrFuncRegex, value.STR_VAL, funcName, pattern)
+	}
+
+	regexCache.add(pattern, re)
+
+	return re, nil
+}
+
+func strArg(funcName string, args []value.Literal) (string, error) {
+	if len(args) == 0 {
+		return "", fail.Errorf(fail.ErrFuncMissingArg, value.STR_VAL, funcName)
+	}
+
+	str, ok := args[0].(*value.Str)
+	if !ok {
+		return "", fail.Errorf(fail.ErrFuncFirstArgStr, value.STR_VAL, funcName)
+	}
+
+	return str.Val, nil
+}
@@ -1,6 +1,11 @@
 package evaluator
 
-import "testing"
+import (
+	"regexp"
+	"testing"
+
+	"github.com/textwire/textwire/v4/config"
+)
 
 func TestEvalStringFunctions(t *testing.T) {
 	cases := []struct {
@@ -185,6 +190,68 @@ func TestEvalStringFunctions(t *testing.T) {
 		{1500, `{{ "%.4f".format(3.14159) }}`, "%.4f"},
 		{1510, `{{ "%%%s%%".format("middle") }}`, "%%middle%%"},
 		{1520, `{{ "%s	%s".format("a", "b") }}`, "a	b"},
+		// replace, replaceAll
+		{1600, `{{ "a-b-c".replace("-", "+") }}`, "a+b-c"},
+		{1610, `{{ "a-b-c".replaceAll("-", "+") }}`, "a+b+c"},
+		{1620, `{{ "привіт світ".replaceAll("і", "i") }}`, "привiт свiт"},
+		{1630, `{{ "abc".replace("x", "y") }}`, "abc"},
+		// startsWith, endsWith
+		{1640, `{{ "https://textwire.dev".startsWith("https://") }}`, "1"},
+		{1650, `{{ "image.png".startsWith("png") }}`, "0"},
+		{1660, `{{ "image.png".endsWith(".png") }}`, "1"},
+		{1670, `{{ "".endsWith("") }}`, "1"},
+		// indexOf
+		{1680, `{{ "hello".indexOf("l") }}`, "2"},
+		{1690, `{{ "привіт".indexOf("ві") }}`, "3"},
+		{1700, `{{ "hello".indexOf("z") }}`, "-1"},
+		// slug
+		{1710, `{{ "Hello, World!".slug() }}`, "hello-world"},
+		{1720, `{{ "  Go 1.27 is out  ".slug() }}`, "go-1-27-is-out"},
+		{1730, `{{ "Привіт Світ".slug("_") }}`, "привіт_світ"},
+		{1740, `{{ "---".slug() }}`, ""},
+		// padLeft, padRight
+		{1750, `{{ "7".padLeft(3, "0") }}`, "007"},
+		{1760, `{{ "ab".padRight(5, "-=") }}`, "ab-=-"},
+		{1770, `{{ "ї".padLeft(3, "☆") }}`, "☆☆ї"},
+		{1780, `{{ "long".padLeft(2) }}`, "long"},
+		{1790, `{{ "a".padRight(3) }}|`, "a  |"},
+		// wordwrap
+		{1800, `{{ "The quick brown fox".wordwrap(10) }}`, "The quick\nbrown fox"},
+		{1810, `{{ "The quick brown fox".wordwrap(10, "|") }}`, "The quick|brown fox"},
+		{
+			1820,
+			`{{ "Supercalifragilistic is long".wordwrap(5, "|") }}`,
+			"Supercalifragilistic|is|long",
+		},
+		{1830, `{{ "ab cd\nef gh".wordwrap(2, "|") }}`, "ab|cd|ef|gh"},
+		// nl2br
+		{1840, `{{ "a\nb".nl2br() }}`, "a<br>\nb"},
+		{1850, `{{ "<b>\r\n".nl2br() }}`, "&lt;b&gt;<br>\r\n"},
+		{1860, `{{ "<b>x</b>\ny".raw().nl2br() }}`, "<b>x</b><br>\ny"},
+		// stripTags
+		{1870, `{{ "<p>Hello <b>World</b></p>".stripTags() }}`, "Hello World"},
+		{1880, `{{ "1 < 2".stripTags() }}`, "1 "},
+		// urlEncode
+		{1890, `{{ "a b&c=д".urlEncode() }}`, "a+b%26c%3D%D0%B4"},
+		// base64
+		{1900, `{{ "Hello".base64() }}`, "SGVsbG8="},
+		{1910, `{{ "".base64() }}`, ""},
+		// sha256
+		{
+			1920,
+			`{{ "abc".sha256() }}`,
+			"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		// match, test
+		{1930, `{{ "order-1234".match("[0-9]+") }}`, "1234"},
+		{
+			1940,
+			`{{ "2026-03-05".match("(\\d+)-(\\d+)").json().raw() }}`,
+			`["2026-03","2026","03"]`,
+		},
+		{1950, `{{ "abc".match("[0-9]").len() }}`, "0"},
+		{1960, `{{ "user@mail.com".test("^[^@]+@[^@]+$") }}`, "1"},
+		{1970, `{{ "not an email".test("@") }}`, "0"},
 	}
 
 	for _, tc := range cases {
@@ -192,6 +259,51 @@ func TestEvalStringFunctions(t *testing.T) {
 	}
 }
 
+func TestEvalNl2brOutputModes(t *testing.T) {
+	cases := []struct {
+		id     uint
+		mode   config.OutputMode
+		expect string
+	}{
+		{10, config.OutputHTML, "&lt;b&gt;<br>\nc"},
+		{20, config.OutputText, "<b>\nc"},
+		{30, config.OutputJSON, `\u003cb\u003e\nc`},
+	}
+
+	for _, tc := range cases {
+		conf := config.New()
+		conf.Output = tc.mode
+
+		evaluated, failure := testEvalWithConfig(`{{ "<b>\nc".nl2br() }}`, conf)
+		if failure != nil {
+			t.Fatalf("Case: %d. evaluation failed: %s", tc.id, failure)
+		}
+
+		if res := evaluated.String(); res != tc.expect {
+			t.Fatalf("Case: %d. Result is not %q, got %q", tc.id, tc.expect, res)
+		}
+	}
+}
+
+func TestRegexCacheIsBounded(t *testing.T) {
+	cache := newRegexLRU(2)
+	for _, pattern := range []string{"a", "b", "a", "c"} {
+		cache.add(pattern, regexp.MustCompile(pattern))
+	}
+
+	if cache.len() != 2 {
+		t.Fatalf("expected 2 cached patterns, got %d", cache.len())
+	}
+
+	if _, ok := cache.get("b"); ok {
+		t.Fatalf("expected the least recently used pattern to be evicted")
+	}
+
+	if _, ok := cache.get("a"); !ok {
+		t.Fatalf("expected recently used pattern to stay cached")
+	}
+}
+
 func TestStringMethodChaining(t *testing.T) {
 	cases := []struct {
 		id     uint
@@ -0,0 +1,141 @@
+package evaluator
+
+import (
+	"time"
+
+	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/i18n"
+	"github.com/textwire/textwire/v4/pkg/value"
+)
+
+// timeYearFunc returns the year of the time
+func timeYearFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Int{Val: int64(receiver.(*value.Time).Val.Year())}, nil
+}
+
+// timeMonthFunc returns the month of the time, from 1 to 12
+func timeMonthFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Int{Val: int64(receiver.(*value.Time).Val.Month())}, nil
+}
+
+// timeDayFunc returns the day of the month
+func timeDayFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Int{Val: int64(receiver.(*value.Time).Val.Day())}, nil
+}
+
+// timeHourFunc returns the hour of the day, from 0 to 23
+func timeHourFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Int{Val: int64(receiver.(*value.Time).Val.Hour())}, nil
+}
+
+// timeMinuteFunc returns the minute of the hour
+func timeMinuteFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Int{Val: int64(receiver.(*value.Time).Val.Minute())}, nil
+}
+
+// timeSecondFunc returns the second of the minute
+func timeSecondFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Int{Val: int64(receiver.(*value.Time).Val.Second())}, nil
+}
+
+// timeUnixFunc returns the time as a Unix timestamp in seconds
+func timeUnixFunc(receiver value.Literal, _ ...value.Literal) (value.Literal, error) {
+	return &value.Int{Val: receiver.(*value.Time).Val.Unix()}, nil
+}
+
+// timeAddFunc adds a duration to the time. The duration is a number of
+// seconds or a string like "24h" or "-1h30m"
+func timeAddFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.TIME_VAL, "add")
+	}
+
+	var dur time.Duration
+
+	switch arg := args[0].(type) {
+	case *value.Int:
+		dur = time.Duration(arg.Val) * time.Second
+	case *value.Str:
+		parsed, err := time.ParseDuration(arg.Val)
+		if err != nil {
+			return nil, fail.Errorf(fail.ErrFuncDuration, value.TIME_VAL, "add", arg.Val)
+		}
+		dur = parsed
+	default:
+		return nil, fail.Errorf(fail.ErrFuncDuration, value.TIME_VAL, "add", args[0].Type())
+	}
+
+	return &value.Time{Val: receiver.(*value.Time).Val.Add(dur)}, nil
+}
+
+// timeBeforeFunc returns true if the time is before the given time
+func timeBeforeFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	other, err := timeArg("before", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return nativeBoolToBoolObj(receiver.(*value.Time).Val.Before(other)), nil
+}
+
+// timeAfterFunc returns true if the time is after the given time
+func timeAfterFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	other, err := timeArg("after", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return nativeBoolToBoolObj(receiver.(*value.Time).Val.After(other)), nil
+}
+
+// timeTzFunc converts the time to the time zone, like "Europe/Berlin"
+func timeTzFunc(receiver value.Literal, args ...value.Literal) (value.Literal, error) {
+	if len(args) == 0 {
+		return nil, fail.Errorf(fail.ErrFuncMissingArg, value.TIME_VAL, "tz")
+	}
+
+	name, ok := args[0].(*value.Str)
+	if !ok {
+		return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.TIME_VAL, "tz")
+	}
+
+	loc, err := time.LoadLocation(name.Val)
+	if err != nil {
+		return nil, fail.Errorf(fail.ErrUnknownTimeZone, name.Val)
+	}
+
+	return &value.Time{Val: receiver.(*value.Time).Val.In(loc)}, nil
+}
+
+// timeFormatFunc formats the time with a Go layout or a named format,
+// like "short" or "long", in the locale of the render call
+func timeFormatFunc(
+	locale *i18n.Locale,
+	receiver value.Literal,
+	args ...value.Literal,
+) (value.Literal, error) {
+	layout := "datetime"
+
+	if len(args) > 0 {
+		layoutArg, ok := args[0].(*value.Str)
+		if !ok {
+			return nil, fail.Errorf(fail.ErrFuncFirstArgStr, value.TIME_VAL, "format")
+		}
+		layout = layoutArg.Val
+	}
+
+	return &value.Str{Val: locale.FormatDate(receiver.(*value.Time).Val, layout)}, nil
+}
+
+func timeArg(funcName string, args []value.Literal) (time.Time, error) {
+	if len(args) == 0 {
+		return time.Time{}, fail.Errorf(fail.ErrFuncMissingArg, value.TIME_VAL, funcName)
+	}
+
+	other, ok := args[0].(*value.Time)
+	if !ok {
+		return time.Time{}, fail.Errorf(fail.ErrFuncFirstArgTime, value.TIME_VAL, funcName)
+	}
+
+	return other.Val, nil
+}
@@ -0,0 +1,121 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/file"
+	"github.com/textwire/textwire/v4/pkg/lexer"
+	"github.com/textwire/textwire/v4/pkg/parser"
+	"github.com/textwire/textwire/v4/pkg/value"
+)
+
+func TestEvalTimeFunctions(t *testing.T) {
+	data := map[string]any{
+		"created": time.Date(2026, 3, 5, 14, 7, 9, 0, time.UTC),
+		"updated": time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC),
+		"empty":   time.Time{},
+	}
+
+	cases := []struct {
+		id     uint
+		inp    string
+		expect string
+	}{
+		{10, `{{ created }}`, "2026-03-05 14:07:09"},
+		{20, `{{ created.year() }}`, "2026"},
+		{30, `{{ created.month() }}`, "3"},
+		{40, `{{ created.day() }}`, "5"},
+		{50, `{{ created.hour() }}`, "14"},
+		{60, `{{ created.minute() }}`, "7"},
+		{70, `{{ created.second() }}`, "9"},
+		{80, `{{ created.unix() }}`, "1772719629"},
+		// add
+		{90, `{{ created.add('24h') }}`, "2026-03-06 14:07:09"},
+		{100, `{{ created.add('-1h30m') }}`, "2026-03-05 12:37:09"},
+		{110, `{{ created.add(60) }}`, "2026-03-05 14:08:09"},
+		// before, after
+		{120, `{{ created.before(updated) }}`, "1"},
+		{130, `{{ created.after(updated) }}`, "0"},
+		// tz
+		{140, `{{ created.tz('Europe/Berlin') }}`, "2026-03-05 15:07:09"},
+		{150, `{{ created.tz('Europe/Berlin').hour() }}`, "15"},
+		// format
+		{160, `{{ created.format() }}`, "03/05/2026 2:07 PM"},
+		{170, `{{ created.format('2006/01/02') }}`, "2026/03/05"},
+		{180, `{{ created.format('long') }}`, "March 5, 2026"},
+		{190, `{{ formatDate(created, 'short') }}`, "03/05/2026"},
+		{200, `{{ formatDate(empty, 'short') }}`, ""},
+		// operators
+		{210, `{{ created < updated }}`, "1"},
+		{220, `{{ created > updated }}`, "0"},
+		{230, `{{ created <= created }}`, "1"},
+		{240, `{{ updated >= created }}`, "1"},
+		{250, `{{ created == created.tz('Asia/Tokyo') }}`, "1"},
+		{260, `{{ created != updated }}`, "1"},
+		{270, `@if(empty)yes@elseno@end`, "no"},
+		{280, `@if(created)yes@end`, "yes"},
+		// json
+		{290, `{{ created.json() }}`, `&#34;2026-03-05T14:07:09Z&#34;`},
+		// equality with strings
+		{300, `{{ created == '2026-03-05 14:07:09' }}`, "1"},
+		{310, `{{ '2026-03-05 14:07:09' == created }}`, "1"},
+		{320, `{{ created != '2026-03-05' }}`, "1"},
+	}
+
+	for _, tc := range cases {
+		evaluated := testEvalWithData(t, tc.inp, data, tc.id)
+
+		if errObj, ok := evaluated.(*value.Error); ok {
+			t.Fatalf("Case: %d. evaluation failed: %s", tc.id, errObj)
+		}
+
+		if res := evaluated.String(); res != tc.expect {
+			t.Fatalf("Case: %d. Result is not %q, got %q", tc.id, tc.expect, res)
+		}
+	}
+}
+
+func TestEvalTimeFunctionsErrors(t *testing.T) {
+	data := map[string]any{"created": time.Date(2026, 3, 5, 14, 7, 9, 0, time.UTC)}
+
+	cases := []struct {
+		id  uint
+		inp string
+	}{
+		{10, `{{ created.add() }}`},
+		{20, `{{ created.add('tomorrow') }}`},
+		{30, `{{ created.before('2026-03-05') }}`},
+		{40, `{{ created.tz('Mars/Olympus') }}`},
+		{50, `{{ created + created }}`},
+		{60, `{{ created < 5 }}`},
+	}
+
+	for _, tc := range cases {
+		evaluated := testEvalWithData(t, tc.inp, data, tc.id)
+
+		if _, ok := evaluated.(*value.Error); !ok {
+			t.Fatalf("Case: %d. Expected error, got %q", tc.id, evaluated.String())
+		}
+	}
+}
+
+func testEvalWithData(t *testing.T, inp string, data map[string]any, id uint) value.Value {
+	l := lexer.New(inp)
+	p := parser.New(l, file.New("file", "to/file", "/path/to/file", nil))
+	prog := p.ParseProgram()
+
+	if p.HasErrors() {
+		t.Fatalf("Case: %d. parsing failed: %s", id, p.Errors()[0])
+	}
+
+	scope, failure := value.NewScopeFromMap(data)
+	if failure != nil {
+		t.Fatalf("Case: %d. scope failed: %s", id, failure)
+	}
+
+	e := New(&config.Func{}, nil)
+
+	return e.Eval(prog, NewContext(scope, prog.AbsPath))
+}
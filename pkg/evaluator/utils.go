@@ -1,12 +1,14 @@
 package evaluator
 
 import (
+	"errors"
 	"fmt"
-	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/ast"
 	"github.com/textwire/textwire/v4/pkg/fail"
 	"github.com/textwire/textwire/v4/pkg/value"
 )
@@ -27,6 +29,8 @@ func isTruthy(obj value.Literal) bool {
 		return len(obj.Pairs) > 0
 	case *value.Arr:
 		return len(obj.Elements) != 0
+	case *value.Time:
+		return !obj.Val.IsZero()
 	case nil:
 		return false
 	}
@@ -38,13 +42,29 @@ func isError(obj value.Value) bool {
 }
 
 func isUndefinedError(obj value.Value) bool {
-	undefinedErrors := []string{
-		fail.ErrVariableIsUndefined,
-		fail.ErrKeyOnNonObj,
+	err, isErr := obj.(*value.Error)
+	if !isErr {
+		return false
 	}
 
-	err, isErr := obj.(*value.Error)
-	return isErr && slices.Contains(undefinedErrors, err.ErrorID)
+	return errors.Is(err.Err, fail.CodeVariableIsUndefined) ||
+		errors.Is(err.Err, fail.CodeKeyOnNonObj) ||
+		errors.Is(err.Err, fail.CodeObjKeyIsUndefined)
+}
+
+// accessName returns a readable name of a variable, member or index
+// access, like "user.address" or "items[0]"
+func accessName(node ast.Expression) string {
+	switch node := node.(type) {
+	case *ast.IdentExpr:
+		return node.Name
+	case *ast.DotExpr:
+		return accessName(node.Left) + "." + node.Key.String()
+	case *ast.IndexExpr:
+		return accessName(node.Left) + "[" + node.Index.String() + "]"
+	}
+
+	return node.String()
 }
 
 func nativeBoolToBoolObj(input bool) value.Literal {
@@ -54,6 +74,94 @@ func nativeBoolToBoolObj(input bool) value.Literal {
 	return FALSE
 }
 
+// valuesEqual compares scalars by value and arrays and objects deeply.
+// Integers and floats are equal when they hold the same number, times
+// are equal to strings in the "2006-01-02 15:04:05" format
+func valuesEqual(a, b value.Literal) bool {
+	switch a := a.(type) {
+	case *value.Int:
+		switch b := b.(type) {
+		case *value.Int:
+			return a.Val == b.Val
+		case *value.Float:
+			return float64(a.Val) == b.Val
+		}
+		return false
+	case *value.Float:
+		switch b := b.(type) {
+		case *value.Float:
+			return a.Val == b.Val
+		case *value.Int:
+			return a.Val == float64(b.Val)
+		}
+		return false
+	case *value.Str:
+		switch b := b.(type) {
+		case *value.Str:
+			return a.Val == b.Val
+		case *value.Time:
+			return a.Val == b.String()
+		}
+		return false
+	case *value.Bool:
+		b, ok := b.(*value.Bool)
+		return ok && a.Val == b.Val
+	case *value.Nil:
+		_, ok := b.(*value.Nil)
+		return ok
+	case *value.Time:
+		switch b := b.(type) {
+		case *value.Time:
+			return a.Val.Equal(b.Val)
+		case *value.Str:
+			return a.String() == b.Val
+		}
+		return false
+	case *value.Arr:
+		b, ok := b.(*value.Arr)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+
+		for i := range a.Elements {
+			if !valuesEqual(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+
+		return true
+	case *value.Obj:
+		b, ok := b.(*value.Obj)
+		if !ok || len(a.Pairs) != len(b.Pairs) {
+			return false
+		}
+
+		for key, val := range a.Pairs {
+			other, ok := b.Pairs[key]
+			if !ok || !valuesEqual(val, other) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return a == b
+}
+
+// concatOperand returns the string that the value adds to
+// a concatenation. Only scalar values can be concatenated
+func concatOperand(val value.Literal) (string, bool) {
+	switch val := val.(type) {
+	case *value.Str:
+		return val.Val, true
+	case *value.Int, *value.Float, *value.Bool, *value.Nil, *value.Time:
+		return val.String(), true
+	}
+
+	return "", false
+}
+
 func hasBreak(obj value.Value) bool {
 	return hasControlStmt(obj, value.BREAK_VAL)
 }
@@ -112,13 +220,13 @@ func getDecimalConfig(
 	decimals = 2
 
 	if len(args) > 2 {
-		return "", 0, fmt.Errorf(fail.ErrFuncMaxArgs, objType, "decimal", 2)
+		return "", 0, fail.Errorf(fail.ErrFuncMaxArgs, objType, "decimal", 2)
 	}
 
 	if len(args) >= 1 {
 		separatorArg, ok := args[0].(*value.Str)
 		if !ok {
-			return "", 0, fmt.Errorf(fail.ErrFuncFirstArgStr, objType, "decimal")
+			return "", 0, fail.Errorf(fail.ErrFuncFirstArgStr, objType, "decimal")
 		}
 		separator = separatorArg.Val
 	}
@@ -126,7 +234,7 @@ func getDecimalConfig(
 	if len(args) == 2 {
 		decimalArg, ok := args[1].(*value.Int)
 		if !ok {
-			return "", 0, fmt.Errorf(fail.ErrFuncSecondArgInt, objType, "decimal")
+			return "", 0, fail.Errorf(fail.ErrFuncSecondArgInt, objType, "decimal")
 		}
 		decimals = int(decimalArg.Val)
 	}
@@ -198,6 +306,22 @@ func getDateTimeLayout(date string) string {
 		return "2006-01-02 15:04:05"
 	case len(date) == 10 && date[4] == '-' && date[7] == '-':
 		return "2006-01-02"
+	case len(date) >= 20 && date[4] == '-' && date[7] == '-' && date[10] == 'T':
+		return time.RFC3339
 	}
 	return ""
 }
+
+// escaper returns the function that escapes strings in the output mode.
+func escaper(mode config.OutputMode) value.Escaper {
+	switch mode {
+	case config.OutputText:
+		return value.EscapeNone
+	case config.OutputJSON:
+		return value.EscapeJSON
+	case config.OutputShell:
+		return value.EscapeShell
+	default:
+		return value.EscapeHTML
+	}
+}
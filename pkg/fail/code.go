@@ -0,0 +1,206 @@
+package fail
+
+// Code is a stable machine-readable identifier of an error. Unlike error
+// messages, codes don't change between releases. Codes implement the error
+// interface, so they can be used as sentinels with errors.Is:
+//
+//	if errors.Is(err, fail.CodeVariableIsUndefined) {
+//		// ...
+//	}
+type Code string
+
+const (
+	// CodeUnknown is used for errors that are not created from
+	// one of the message constants, like I/O errors.
+	CodeUnknown Code = "unknown"
+
+	// Parser errors
+	CodeEmptyBraces            Code = "empty_braces"
+	CodeWrongPeekToken         Code = "wrong_peek_token"
+	CodeExpectExprAfter        Code = "expect_expr_after"
+	CodeCouldNotParseAs        Code = "could_not_parse_as"
+	CodeIllegalToken           Code = "illegal_token"
+	CodeElseifCannotFollowElse Code = "elseif_cannot_follow_else"
+	CodeExpectedObjLit         Code = "expected_obj_lit"
+	CodeSlotNotDefined         Code = "slot_not_defined"
+	CodeDuplicateReserves      Code = "duplicate_reserves"
+	CodeDuplicateSlots         Code = "duplicate_slots"
+	CodeDuplicatePass          Code = "duplicate_pass"
+	CodeDuplicateInserts       Code = "duplicate_inserts"
+	CodeUnusedInsertDetected   Code = "unused_insert_detected"
+	CodeOnlyOneUseDir          Code = "only_one_use_dir"
+	CodeForLoopExpectStmt      Code = "for_loop_expect_stmt"
+	CodeWrongTokenType         Code = "wrong_token_type"
+	CodeNameCannotBeEmpty      Code = "name_cannot_be_empty"
+	CodeGlobalFuncFewArgs      Code = "global_func_few_args"
+	CodeGlobalFuncLotsOfArgs   Code = "global_func_lots_of_args"
+	CodeCacheDirArgs           Code = "cache_dir_args"
+	CodeLoopDirInCache         Code = "loop_dir_in_cache"
+	CodeSwitchExpectsCase      Code = "switch_expects_case"
+	CodeCaseDirArgs            Code = "case_dir_args"
+	CodeCaseAfterDefault       Code = "case_after_default"
+
+	// Evaluator (interpreter) errors
+	CodeUnknownType           Code = "unknown_type"
+	CodeInsertMustHaveContent Code = "insert_must_have_content"
+	CodeIndexNotSupported     Code = "index_not_supported"
+	CodeUnknownOp             Code = "unknown_op"
+	CodeCannotUseOperator     Code = "cannot_use_operator"
+	CodeCannotDecFromFloat    Code = "cannot_dec_from_float"
+	CodePrefixOpIsWrong       Code = "prefix_op_is_wrong"
+	CodeVariableIsUndefined   Code = "variable_is_undefined"
+	CodeReservedIdentifiers   Code = "reserved_identifiers"
+	CodeIdentTypeMismatch     Code = "ident_type_mismatch"
+	CodeNotSupportedAssign    Code = "not_supported_assign"
+	CodeDivisionByZero        Code = "division_by_zero"
+	CodeEachDirWithNonArrArg  Code = "each_dir_with_non_arr_arg"
+	CodeArrIndexInt           Code = "arr_index_int"
+	CodeArrIndexOutOfBound    Code = "arr_index_out_of_bound"
+	CodeTemplateDirectives    Code = "template_directives"
+	CodeInsertRequiresUse     Code = "insert_requires_use"
+	CodeUseDirMissingLayout   Code = "use_dir_missing_layout"
+	CodeGlobalFuncMissing     Code = "global_func_missing"
+	CodeGlobalFuncWrongType   Code = "global_func_wrong_type"
+	CodeFormatDateWrongDate   Code = "format_date_wrong_date"
+	CodeFormatDateParseErr    Code = "format_date_parse_err"
+	CodeRelativeTimeWrongDate Code = "relative_time_wrong_date"
+	CodeUnknownTimeZone       Code = "unknown_time_zone"
+	CodeKeyOnNonObj           Code = "key_on_non_obj"
+	CodeObjKeyIsUndefined     Code = "obj_key_is_undefined"
+	CodeIllegalTypeForInc     Code = "illegal_type_for_inc"
+	CodeIllegalTypeForDec     Code = "illegal_type_for_dec"
+	CodeUseDirIsNotAllowed    Code = "use_dir_is_not_allowed"
+	CodeCacheKeyType          Code = "cache_key_type"
+	CodeCacheTTLType          Code = "cache_ttl_type"
+	CodeRangeZeroStep         Code = "range_zero_step"
+	CodeRangeTooLarge         Code = "range_too_large"
+
+	// Functions
+	CodeFuncNotDefined   Code = "func_not_defined"
+	CodeFuncMissingArg   Code = "func_missing_arg"
+	CodeFuncFirstArgInt  Code = "func_first_arg_int"
+	CodeFuncFirstArgStr  Code = "func_first_arg_str"
+	CodeFuncSecondArgInt Code = "func_second_arg_int"
+	CodeFuncSecondArgStr Code = "func_second_arg_str"
+	CodeFuncMaxArgs      Code = "func_max_args"
+	CodeFuncFirstArgTime Code = "func_first_arg_time"
+	CodeFuncDuration     Code = "func_duration"
+	CodeFuncFirstArgKey  Code = "func_first_arg_key"
+	CodeFuncArgStr       Code = "func_arg_str"
+	CodeFuncArgObj       Code = "func_arg_obj"
+	CodeFuncArgNum       Code = "func_arg_num"
+	CodeFuncRegex        Code = "func_regex"
+	CodeFuncPositiveArg  Code = "func_positive_arg"
+	CodeFuncNumericElems Code = "func_numeric_elems"
+	CodeFuncStrTooLong   Code = "func_str_too_long"
+	CodeCustomFuncFailed Code = "custom_func_failed"
+
+	// Template errors
+	CodeUnsupportedType    Code = "unsupported_type"
+	CodeTemplateNotFound   Code = "template_not_found"
+	CodeFuncAlreadyDefined Code = "func_already_defined"
+	CodeFuncIsBuiltIn      Code = "func_is_built_in"
+
+	// Linker errors
+	CodeDefaultSlotNotDefined Code = "default_slot_not_defined"
+	CodeUndefinedComponent    Code = "undefined_component"
+)
+
+// codes maps message constants to their codes.
+var codes = map[string]Code{
+	ErrEmptyBraces:            CodeEmptyBraces,
+	ErrWrongPeekToken:         CodeWrongPeekToken,
+	ErrExpectExprAfter:        CodeExpectExprAfter,
+	ErrCouldNotParseAs:        CodeCouldNotParseAs,
+	ErrIllegalToken:           CodeIllegalToken,
+	ErrElseifCannotFollowElse: CodeElseifCannotFollowElse,
+	ErrExpectedObjLit:         CodeExpectedObjLit,
+	ErrSlotNotDefined:         CodeSlotNotDefined,
+	ErrDuplicateReserves:      CodeDuplicateReserves,
+	ErrDuplicateSlots:         CodeDuplicateSlots,
+	ErrDuplicatePass:          CodeDuplicatePass,
+	ErrDuplicateInserts:       CodeDuplicateInserts,
+	ErrUnusedInsertDetected:   CodeUnusedInsertDetected,
+	ErrOnlyOneUseDir:          CodeOnlyOneUseDir,
+	ErrForLoopExpectStmt:      CodeForLoopExpectStmt,
+	ErrWrongTokenType:         CodeWrongTokenType,
+	ErrNameCannotBeEmpty:      CodeNameCannotBeEmpty,
+	ErrGlobalFuncFewArgs:      CodeGlobalFuncFewArgs,
+	ErrGlobalFuncLotsOfArgs:   CodeGlobalFuncLotsOfArgs,
+	ErrCacheDirArgs:           CodeCacheDirArgs,
+	ErrLoopDirInCache:         CodeLoopDirInCache,
+	ErrSwitchExpectsCase:      CodeSwitchExpectsCase,
+	ErrCaseDirArgs:            CodeCaseDirArgs,
+	ErrCaseAfterDefault:       CodeCaseAfterDefault,
+	ErrUnknownType:            CodeUnknownType,
+	ErrInsertMustHaveContent:  CodeInsertMustHaveContent,
+	ErrIndexNotSupported:      CodeIndexNotSupported,
+	ErrUnknownOp:              CodeUnknownOp,
+	ErrCannotUseOperator:      CodeCannotUseOperator,
+	ErrCannotDecFromFloat:     CodeCannotDecFromFloat,
+	ErrPrefixOpIsWrong:        CodePrefixOpIsWrong,
+	ErrVariableIsUndefined:    CodeVariableIsUndefined,
+	ErrReservedIdentifiers:    CodeReservedIdentifiers,
+	ErrIdentTypeMismatch:      CodeIdentTypeMismatch,
+	ErrNotSupportedAssign:     CodeNotSupportedAssign,
+	ErrDivisionByZero:         CodeDivisionByZero,
+	ErrEachDirWithNonArrArg:   CodeEachDirWithNonArrArg,
+	ErrArrIndexInt:            CodeArrIndexInt,
+	ErrArrIndexOutOfBound:     CodeArrIndexOutOfBound,
+	ErrTemplateDirectives:     CodeTemplateDirectives,
+	ErrInsertRequiresUse:      CodeInsertRequiresUse,
+	ErrUseDirMissingLayout:    CodeUseDirMissingLayout,
+	ErrGlobalFuncMissing:      CodeGlobalFuncMissing,
+	ErrGlobalFuncWrongType:    CodeGlobalFuncWrongType,
+	ErrFormatDateWrongDate:    CodeFormatDateWrongDate,
+	ErrFormatDateParseErr:     CodeFormatDateParseErr,
+	ErrRelativeTimeWrongDate:  CodeRelativeTimeWrongDate,
+	ErrUnknownTimeZone:        CodeUnknownTimeZone,
+	ErrKeyOnNonObj:            CodeKeyOnNonObj,
+	ErrObjKeyIsUndefined:      CodeObjKeyIsUndefined,
+	ErrIllegalTypeForInc:      CodeIllegalTypeForInc,
+	ErrIllegalTypeForDec:      CodeIllegalTypeForDec,
+	ErrUseDirIsNotAllowed:     CodeUseDirIsNotAllowed,
+	ErrCacheKeyType:           CodeCacheKeyType,
+	ErrCacheTTLType:           CodeCacheTTLType,
+	ErrRangeZeroStep:          CodeRangeZeroStep,
+	ErrRangeTooLarge:          CodeRangeTooLarge,
+	ErrFuncNotDefined:         CodeFuncNotDefined,
+	ErrFuncMissingArg:         CodeFuncMissingArg,
+	ErrFuncFirstArgInt:        CodeFuncFirstArgInt,
+	ErrFuncFirstArgStr:        CodeFuncFirstArgStr,
+	ErrFuncSecondArgInt:       CodeFuncSecondArgInt,
+	ErrFuncSecondArgStr:       CodeFuncSecondArgStr,
+	ErrFuncMaxArgs:            CodeFuncMaxArgs,
+	ErrFuncFirstArgTime:       CodeFuncFirstArgTime,
+	ErrFuncDuration:           CodeFuncDuration,
+	ErrFuncFirstArgKey:        CodeFuncFirstArgKey,
+	ErrFuncArgStr:             CodeFuncArgStr,
+	ErrFuncArgObj:             CodeFuncArgObj,
+	ErrFuncArgNum:             CodeFuncArgNum,
+	ErrFuncRegex:              CodeFuncRegex,
+	ErrFuncPositiveArg:        CodeFuncPositiveArg,
+	ErrFuncNumericElems:       CodeFuncNumericElems,
+	ErrFuncStrTooLong:         CodeFuncStrTooLong,
+	ErrCustomFuncFailed:       CodeCustomFuncFailed,
+	ErrUnsupportedType:        CodeUnsupportedType,
+	ErrTemplateNotFound:       CodeTemplateNotFound,
+	ErrFuncAlreadyDefined:     CodeFuncAlreadyDefined,
+	ErrFuncIsBuiltIn:          CodeFuncIsBuiltIn,
+	ErrDefaultSlotNotDefined:  CodeDefaultSlotNotDefined,
+	ErrUndefinedComponent:     CodeUndefinedComponent,
+}
+
+// Error returns the code itself to implement the error interface.
+func (c Code) Error() string {
+	return string(c)
+}
+
+// codeOf returns the code of the given message constant.
+func codeOf(msg string) Code {
+	if code, ok := codes[msg]; ok {
+		return code
+	}
+
+	return CodeUnknown
+}
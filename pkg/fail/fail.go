@@ -4,12 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"strings"
 
 	"github.com/textwire/textwire/v4/pkg/position"
 )
 
-// Each string constant here is also an ErrorID on Error object.
-// It helps to identify error by checking if ErrorID == fail.ErrEmptyBraces.
+// Each string constant here is also an ErrorID on Error object and has
+// a matching Code. Use Code with errors.Is to identify errors, like
+// errors.Is(err, fail.CodeEmptyBraces).
 const (
 	// Parser errors
 	ErrEmptyBraces            = "empty expression {{}} - must contain valid code like {{ variable }} or {{ 1 + 2 }}"
@@ -31,6 +34,11 @@ const (
 	ErrNameCannotBeEmpty      = "'%s' name cannot be empty"
 	ErrGlobalFuncFewArgs      = "global function %s() must have at least '%d' arguments, got '%d'"
 	ErrGlobalFuncLotsOfArgs   = "global function %s() can have maximum '%d' arguments, got '%d'"
+	ErrCacheDirArgs           = "@cache() requires a key and an optional TTL, got '%d' arguments"
+	ErrLoopDirInCache         = "%s cannot stop a loop outside of @cache(), cached content is rendered without it"
+	ErrSwitchExpectsCase      = "@switch() can only contain @case and @default directives, got '%s'"
+	ErrCaseDirArgs            = "@case() requires at least one value"
+	ErrCaseAfterDefault       = "'@case' cannot come after '@default'"
 
 	// Evaluator (interpreter) errors
 	ErrUnknownType           = "unsupported type '%T'"
@@ -55,10 +63,17 @@ const (
 	ErrGlobalFuncWrongType   = "global function %s() must have type '%s' as argument '%d', got '%s'"
 	ErrFormatDateWrongDate   = "global function formatDate() doesn't support date format '%s'"
 	ErrFormatDateParseErr    = "cannot parse date '%s' with layout '%s'"
+	ErrRelativeTimeWrongDate = "global function relativeTime() doesn't support date format '%s'"
+	ErrUnknownTimeZone       = "unknown time zone '%s'"
 	ErrKeyOnNonObj           = "'%s' type does not support attribute '%s' access"
+	ErrObjKeyIsUndefined     = "object does not have key '%s'"
 	ErrIllegalTypeForInc     = "cannot increment '%s', only integer and float are allowed"
 	ErrIllegalTypeForDec     = "cannot decrement '%s', only integer and float are allowed"
 	ErrUseDirIsNotAllowed    = "@use() not allowed in layout files - causes infinite recursion"
+	ErrCacheKeyType          = "@cache() key must be a string or a number, got '%s'"
+	ErrCacheTTLType          = "@cache() TTL must be a number of seconds or a duration like '5m', got '%s'"
+	ErrRangeZeroStep         = "global function range() step cannot be zero"
+	ErrRangeTooLarge         = "global function range() can create at most %d elements, got %d"
 
 	// Functions
 	ErrFuncNotDefined   = "%s.%s() is not defined"
@@ -68,11 +83,23 @@ const (
 	ErrFuncSecondArgInt = "argument 2 on %s.%s() must be 'integer'"
 	ErrFuncSecondArgStr = "argument 2 on %s.%s() must be 'string'"
 	ErrFuncMaxArgs      = "%s.%s() takes at most %d arguments"
+	ErrFuncFirstArgTime = "argument 1 on %s.%s() must be 'time'"
+	ErrFuncDuration     = "argument 1 on %s.%s() must be a number of seconds or a duration like '24h', got '%s'"
+	ErrFuncFirstArgKey  = "argument 1 on %s.%s() must be a key 'string' or a 'lambda'"
+	ErrFuncArgStr       = "argument %d on %s.%s() must be 'string'"
+	ErrFuncArgObj       = "argument %d on %s.%s() must be 'object'"
+	ErrFuncArgNum       = "argument %d on %s.%s() must be 'integer' or 'float'"
+	ErrFuncRegex        = "argument 1 on %s.%s() must be a valid regular expression, got '%s'"
+	ErrFuncPositiveArg  = "argument 1 on %s.%s() must be a positive 'integer'"
+	ErrFuncNumericElems = "%s.%s() works only with numbers, got '%s' element"
+	ErrFuncStrTooLong   = "%s.%s() can create strings of at most %d characters, got %d"
+	ErrCustomFuncFailed = "custom function %s.%s() failed: %s"
 
 	// Template errors
 	ErrUnsupportedType    = "unsupported value type '%T'"
 	ErrTemplateNotFound   = "template file '%s' not found"
 	ErrFuncAlreadyDefined = "custom function '%s' already defined for type '%s'"
+	ErrFuncIsBuiltIn      = "function '%s' is built-in for type '%s' and can't be redefined"
 
 	// Linker errors
 	ErrDefaultSlotNotDefined = "you are passing default content in your @component('%s'), but default @slot is not defined in component file '%s'"
@@ -95,9 +122,52 @@ type Error struct {
 	origin   ErrOrigin
 	filepath string
 	message  string
+	code     Code
+
+	// cause is the underlying error, like an I/O error. Can be nil.
+	cause error
+
+	// stack is the template call stack, the innermost frame is first.
+	stack []Frame
+
+	// vars holds JSON representation of variables that were in scope
+	// when the error happened. It's only set in DebugMode. When varsFn
+	// is set, vars are built with it the first time they are needed.
+	vars   map[string]string
+	varsFn func() map[string]string
 }
 
-// New creates a new Error instance of Error
+// FrameKind describes how the template of a Frame was entered.
+type FrameKind string
+
+const (
+	FramePage      FrameKind = "page"      // Template rendered by Template.String()
+	FrameLayout    FrameKind = "layout"    // Layout entered with @use
+	FrameInsert    FrameKind = "insert"    // Content of @insert for the layout
+	FrameComponent FrameKind = "component" // Component entered with @component
+	FrameSlot      FrameKind = "slot"      // Content of @pass for the component slot
+)
+
+// Frame is a single entry of the template call stack, like a layout
+// used by a page or a component rendered inside of a layout.
+type Frame struct {
+	Kind FrameKind
+
+	// Name of the template, like "home" or "components/book".
+	// For inserts and slots, it's the name of the insert or the slot.
+	Name string
+
+	// Filepath is the absolute path to the file with the directive
+	// that entered this frame.
+	Filepath string
+
+	// Pos is the position of the directive that entered this frame.
+	// It is empty for the page frame.
+	Pos *position.Pos
+}
+
+// New creates a new Error instance of Error. The code is derived from msg
+// when it's one of the message constants, otherwise it's CodeUnknown.
 func New(pos *position.Pos, filepath string, origin ErrOrigin, msg string, args ...any) *Error {
 	if pos == nil {
 		pos = &position.Pos{}
@@ -108,9 +178,33 @@ func New(pos *position.Pos, filepath string, origin ErrOrigin, msg string, args
 		origin:   origin,
 		filepath: filepath,
 		message:  fmt.Sprintf(msg, args...),
+		code:     codeOf(msg),
 	}
 }
 
+// Wrap creates a new Error like New does and sets err as its cause,
+// so that it can be inspected with errors.Is and errors.As.
+func Wrap(
+	err error,
+	pos *position.Pos,
+	filepath string,
+	origin ErrOrigin,
+	msg string,
+	args ...any,
+) *Error {
+	failure := New(pos, filepath, origin, msg, args...)
+	failure.cause = err
+	return failure
+}
+
+// Errorf returns an error without position and file path that keeps
+// the code of the message constant. Use it in places that don't know
+// where the error happened, like built-in functions. FromError copies
+// its code when the error gets a position.
+func Errorf(msg string, args ...any) error {
+	return New(nil, "", OriginEval, msg, args...)
+}
+
 func (e *Error) Filepath() string {
 	return e.filepath
 }
@@ -127,6 +221,83 @@ func (e *Error) Message() string {
 	return e.message
 }
 
+// Code returns the stable machine-readable code of the error.
+func (e *Error) Code() Code {
+	return e.code
+}
+
+// Stack returns the template call stack with the innermost frame first.
+// It's empty when the error happened outside of templates.
+func (e *Error) Stack() []Frame {
+	return e.stack
+}
+
+// WithStack sets the template call stack and returns the same error.
+func (e *Error) WithStack(stack []Frame) *Error {
+	e.stack = stack
+	return e
+}
+
+// Vars returns variables that were in scope when the error happened,
+// with their values encoded as JSON. It's only set in DebugMode.
+func (e *Error) Vars() map[string]string {
+	if e.varsFn != nil {
+		e.vars = e.varsFn()
+		e.varsFn = nil
+	}
+
+	return e.vars
+}
+
+// WithVars sets variables in scope and returns the same error.
+func (e *Error) WithVars(vars map[string]string) *Error {
+	e.vars = vars
+	e.varsFn = nil
+	return e
+}
+
+// WithVarsFunc sets a function that returns variables in scope and
+// returns the same error. The function is called once, the first time
+// Vars is called, so errors that are never shown cost nothing.
+func (e *Error) WithVarsFunc(fn func() map[string]string) *Error {
+	e.varsFn = fn
+	return e
+}
+
+// LogAttrs returns attributes that describe the error in structured logs:
+// the message, code, template name, file path and position.
+func (e *Error) LogAttrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, 6)
+	attrs = append(attrs, slog.String("error", e.message), slog.String("code", string(e.code)))
+
+	if name := e.templateName(); name != "" {
+		attrs = append(attrs, slog.String("template", name))
+	}
+
+	if e.filepath != "" {
+		attrs = append(attrs, slog.String("path", e.filepath))
+	}
+
+	return append(
+		attrs,
+		slog.Uint64("line", uint64(e.pos.Line())),
+		slog.Uint64("col", uint64(e.pos.Col())),
+	)
+}
+
+// templateName returns the name of the template where the error happened.
+// Content of inserts and slots is written in the template that entered
+// the layout or the component, which is two frames below on the stack.
+func (e *Error) templateName() string {
+	for i := 0; i < len(e.stack); i += 2 {
+		if kind := e.stack[i].Kind; kind != FrameInsert && kind != FrameSlot {
+			return e.stack[i].Name
+		}
+	}
+
+	return ""
+}
+
 // Meta returns the error meta information like the file path and line number
 func (e *Error) Meta() string {
 	var path string
@@ -141,6 +312,33 @@ func (e *Error) String() string {
 	return fmt.Sprintf("[%s]: %s", e.Meta(), e.Message())
 }
 
+// StackTrace returns the template call stack formatted like a Go stack
+// trace, one frame per two lines. It's empty when there is no stack.
+func (e *Error) StackTrace() string {
+	var out strings.Builder
+
+	for _, frame := range e.stack {
+		out.WriteString(frame.String())
+		out.WriteString("\n")
+	}
+
+	return out.String()
+}
+
+// String returns the frame with its kind, name and location.
+func (f Frame) String() string {
+	name := f.Name
+	if f.Kind == FrameInsert || f.Kind == FrameSlot {
+		name = fmt.Sprintf("'%s'", f.Name)
+	}
+
+	if f.Pos == nil || f.Kind == FramePage {
+		return fmt.Sprintf("%s %s\n\t%s", f.Kind, name, f.Filepath)
+	}
+
+	return fmt.Sprintf("%s %s\n\t%s:%d", f.Kind, name, f.Filepath, f.Pos.Line())
+}
+
 // FatalOnError calls log.Fatal if the error message is not empty
 func (e *Error) FatalOnError() {
 	if e == nil {
@@ -169,11 +367,28 @@ func (e *Error) PrintOnError() {
 	log.Println(e.String())
 }
 
-func (e *Error) Error() error {
-	return errors.New(e.String())
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.String()
 }
 
-func FromError(err error, pos *position.Pos, absPath string, origin ErrOrigin, args ...any) *Error {
+// Unwrap returns the underlying error, like an I/O error or an error
+// returned by a custom function.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether the error has the target code, this way codes
+// can be used as sentinels with errors.Is.
+func (e *Error) Is(target error) bool {
+	code, ok := target.(Code)
+	return ok && code == e.code
+}
+
+// FromError creates an Error with the given position and file path that
+// wraps err. The code is copied when err is an Error, otherwise it's
+// CodeUnknown. The message of err is used as is, it's never a format.
+func FromError(err error, pos *position.Pos, absPath string, origin ErrOrigin) *Error {
 	if err == nil {
 		panic("err should never be nil in fail.FromError() function")
 	}
@@ -182,5 +397,13 @@ func FromError(err error, pos *position.Pos, absPath string, origin ErrOrigin, a
 		pos = &position.Pos{}
 	}
 
-	return New(pos, absPath, origin, err.Error(), args...)
+	failure := Wrap(err, pos, absPath, origin, "%s", err.Error())
+
+	var inner *Error
+	if errors.As(err, &inner) {
+		failure.code = inner.code
+		failure.message = inner.message
+	}
+
+	return failure
 }
@@ -26,6 +26,10 @@ type SourceFile struct {
 	// Abs is the absolute path to the source file starting with `/`.
 	Abs string
 
+	// Path is the slash-separated path to the source file inside of
+	// config.TemplateFS. The file watcher uses it to match changed files.
+	Path string
+
 	// ModTime is when the file was last modified.
 	ModTime time.Time
 
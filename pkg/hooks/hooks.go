@@ -0,0 +1,133 @@
+// Package hooks lets you observe rendering of templates, like measuring
+// how long each template, component or function call takes.
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/textwire/textwire/v4/pkg/position"
+)
+
+// Hooks receives events while templates are rendered. Start methods return
+// the context that is passed to the matching End method and to all events
+// that happen in between, which lets tracers nest spans. Templates can be
+// rendered from multiple goroutines, so hooks must be safe for concurrent
+// use. Embed Nop to implement only the methods you need.
+type Hooks interface {
+	OnRenderStart(ctx context.Context, r Render) context.Context
+	OnRenderEnd(ctx context.Context, r Render)
+	OnComponentStart(ctx context.Context, c Component) context.Context
+	OnComponentEnd(ctx context.Context, c Component)
+	OnFunctionCall(ctx context.Context, call FunctionCall)
+}
+
+// Render describes rendering of a template by Template.String()
+// or Template.Response().
+type Render struct {
+	// Template is the name of the template, like "home".
+	Template string
+
+	// Path is the absolute path to the template file.
+	Path string
+
+	Start time.Time
+
+	// Duration, Size and Err are only set for OnRenderEnd.
+	Duration time.Duration
+
+	// Size is the length of the output in bytes.
+	Size int
+	Err  error
+}
+
+// Component describes rendering of a component with @component directive.
+type Component struct {
+	// Name of the component, like "book" or "components/book".
+	Name string
+
+	// Path is the absolute path to the component file.
+	Path string
+
+	// Caller is the absolute path to the file with @component directive.
+	Caller string
+
+	// Pos is the position of @component directive in the Caller.
+	Pos *position.Pos
+
+	Start time.Time
+
+	// Duration, Size and Err are only set for OnComponentEnd.
+	Duration time.Duration
+
+	// Size is the length of the component output in bytes.
+	Size int
+	Err  error
+}
+
+// FunctionCall describes a call of a built-in, custom or global function.
+type FunctionCall struct {
+	// Receiver is the type of the value the function is called on, like
+	// "string". It's empty for global functions, like formatDate().
+	Receiver string
+
+	Name string
+
+	// Path is the absolute path to the file with the call.
+	Path string
+
+	Pos      *position.Pos
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// Nop implements Hooks with methods that do nothing.
+type Nop struct{}
+
+func (Nop) OnRenderStart(ctx context.Context, _ Render) context.Context       { return ctx }
+func (Nop) OnRenderEnd(context.Context, Render)                               {}
+func (Nop) OnComponentStart(ctx context.Context, _ Component) context.Context { return ctx }
+func (Nop) OnComponentEnd(context.Context, Component)                         {}
+func (Nop) OnFunctionCall(context.Context, FunctionCall)                      {}
+
+// multi calls every hook in order.
+type multi []Hooks
+
+// Multi returns Hooks that call each of the given hooks in order.
+// Contexts returned by start methods are passed from one hook to the next.
+func Multi(hooks ...Hooks) Hooks {
+	return multi(hooks)
+}
+
+func (m multi) OnRenderStart(ctx context.Context, r Render) context.Context {
+	for _, h := range m {
+		ctx = h.OnRenderStart(ctx, r)
+	}
+	return ctx
+}
+
+func (m multi) OnRenderEnd(ctx context.Context, r Render) {
+	for _, h := range m {
+		h.OnRenderEnd(ctx, r)
+	}
+}
+
+func (m multi) OnComponentStart(ctx context.Context, c Component) context.Context {
+	for _, h := range m {
+		ctx = h.OnComponentStart(ctx, c)
+	}
+	return ctx
+}
+
+func (m multi) OnComponentEnd(ctx context.Context, c Component) {
+	for _, h := range m {
+		h.OnComponentEnd(ctx, c)
+	}
+}
+
+func (m multi) OnFunctionCall(ctx context.Context, call FunctionCall) {
+	for _, h := range m {
+		h.OnFunctionCall(ctx, call)
+	}
+}
@@ -0,0 +1,131 @@
+package hooks
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer starts spans. Implement it with your tracing SDK, like
+// OpenTelemetry, to see templates, components and function calls
+// as spans of your traces:
+//
+//	func (t otelTracer) Start(
+//		ctx context.Context,
+//		name string,
+//		start time.Time,
+//		attrs ...hooks.Attr,
+//	) (context.Context, hooks.Span) {
+//		ctx, span := t.tracer.Start(ctx, name, trace.WithTimestamp(start))
+//		span.SetAttributes(toOtelAttrs(attrs)...)
+//		return ctx, otelSpan{span}
+//	}
+type Tracer interface {
+	Start(ctx context.Context, name string, start time.Time, attrs ...Attr) (context.Context, Span)
+}
+
+// Span is a single operation started by Tracer.
+type Span interface {
+	SetAttributes(attrs ...Attr)
+	RecordError(err error)
+	End(end time.Time)
+}
+
+// Attr is a span attribute. Values are strings or integers.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// Span names and attribute keys used by Tracing.
+const (
+	SpanRender    = "textwire.render"
+	SpanComponent = "textwire.component"
+	SpanFunction  = "textwire.function"
+
+	AttrTemplate  = "textwire.template"
+	AttrComponent = "textwire.component"
+	AttrFunction  = "textwire.function"
+	AttrReceiver  = "textwire.receiver"
+	AttrPath      = "textwire.path"
+	AttrLine      = "textwire.line"
+	AttrSize      = "textwire.size"
+)
+
+type spanKey struct{}
+
+type tracing struct {
+	tracer Tracer
+}
+
+// Tracing returns Hooks that start a span for every rendered template,
+// component and function call. Component spans are children of the
+// template span and function spans are children of the innermost one.
+func Tracing(tracer Tracer) Hooks {
+	return &tracing{tracer: tracer}
+}
+
+func (t *tracing) OnRenderStart(ctx context.Context, r Render) context.Context {
+	return t.start(ctx, SpanRender, r.Start, Attr{AttrTemplate, r.Template}, Attr{AttrPath, r.Path})
+}
+
+func (t *tracing) OnRenderEnd(ctx context.Context, r Render) {
+	end(ctx, r.Start.Add(r.Duration), r.Size, r.Err)
+}
+
+func (t *tracing) OnComponentStart(ctx context.Context, c Component) context.Context {
+	return t.start(
+		ctx,
+		SpanComponent,
+		c.Start,
+		Attr{AttrComponent, c.Name},
+		Attr{AttrPath, c.Path},
+	)
+}
+
+func (t *tracing) OnComponentEnd(ctx context.Context, c Component) {
+	end(ctx, c.Start.Add(c.Duration), c.Size, c.Err)
+}
+
+func (t *tracing) OnFunctionCall(ctx context.Context, call FunctionCall) {
+	attrs := []Attr{{AttrFunction, call.Name}, {AttrPath, call.Path}}
+	if call.Receiver != "" {
+		attrs = append(attrs, Attr{AttrReceiver, call.Receiver})
+	}
+
+	if call.Pos != nil {
+		attrs = append(attrs, Attr{AttrLine, int(call.Pos.Line())})
+	}
+
+	_, span := t.tracer.Start(ctx, SpanFunction, call.Start, attrs...)
+	if call.Err != nil {
+		span.RecordError(call.Err)
+	}
+
+	span.End(call.Start.Add(call.Duration))
+}
+
+func (t *tracing) start(
+	ctx context.Context,
+	name string,
+	start time.Time,
+	attrs ...Attr,
+) context.Context {
+	ctx, span := t.tracer.Start(ctx, name, start, attrs...)
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// end ends the span stored in the context by start.
+func end(ctx context.Context, endTime time.Time, size int, err error) {
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	} else {
+		span.SetAttributes(Attr{AttrSize, size})
+	}
+
+	span.End(endTime)
+}
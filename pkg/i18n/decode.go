@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func decode(ext string, data []byte) (map[string]any, error) {
+	if ext == ".json" {
+		messages := map[string]any{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, err
+		}
+		return messages, nil
+	}
+
+	return decodeYAML(data)
+}
+
+// decodeYAML decodes the subset of YAML used by translation files:
+// nested objects with string values, comments and quoted strings.
+func decodeYAML(data []byte) (map[string]any, error) {
+	type level struct {
+		indent   int
+		messages map[string]any
+	}
+
+	root := map[string]any{}
+	stack := []level{{indent: -1, messages: root}}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == "---" || trimmed[0] == '#' {
+			continue
+		}
+
+		if trimmed[0] == '\t' {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+
+		indent := len(line) - len(trimmed)
+		for indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		key, val, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.HasPrefix(trimmed, "- ") {
+			return nil, fmt.Errorf("line %d: expected 'key: value'", i+1)
+		}
+
+		key, err := yamlScalar(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", i+1)
+		}
+
+		parent := stack[len(stack)-1].messages
+		val = strings.TrimSpace(val)
+
+		if val == "" {
+			child := map[string]any{}
+			parent[key] = child
+			stack = append(stack, level{indent: indent, messages: child})
+			continue
+		}
+
+		if parent[key], err = yamlScalar(val); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+
+	return root, nil
+}
+
+// yamlScalar decodes a plain, single-quoted or double-quoted string.
+// Plain strings can't be empty, use quotes for an empty string.
+func yamlScalar(s string) (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("expected a value")
+	}
+
+	switch s[0] {
+	case '"':
+		end := strings.LastIndexByte(s, '"')
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string %s", s)
+		}
+		return strconv.Unquote(s[:end+1])
+	case '\'':
+		end := strings.LastIndexByte(s, '\'')
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string %s", s)
+		}
+		return strings.ReplaceAll(s[1:end], "''", "'"), nil
+	case '#':
+		return "", fmt.Errorf("expected a value")
+	case '|', '>', '[', '{', '&', '*':
+		return "", fmt.Errorf("unsupported value %s", s)
+	}
+
+	if idx := strings.Index(s, " #"); idx != -1 {
+		s = strings.TrimSpace(s[:idx])
+	}
+
+	if s == "" {
+		return "", fmt.Errorf("expected a value")
+	}
+
+	return s, nil
+}
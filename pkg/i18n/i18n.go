@@ -0,0 +1,230 @@
+// Package i18n translates messages for the __() global function.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Translator translates message keys, like "messages.welcome", for the
+// given locale. Params replace placeholders like "{name}" in messages
+// and "count" param chooses the plural form. It returns false when
+// the message is not found.
+type Translator interface {
+	Translate(locale, key string, params map[string]any) (string, bool)
+}
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx with the locale that is used by
+// Template.StringContext to translate messages.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFrom returns the locale set with WithLocale or an empty string.
+func LocaleFrom(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// Catalog is a Translator that keeps messages in memory. Messages are
+// strings or plural forms, like {"one": "{count} item", "other": "{count}
+// items"}. Exact matches, like "=0", take precedence over plural forms.
+type Catalog struct {
+	// Fallback is the locale used when a message is missing in
+	// the requested locale.
+	Fallback string
+
+	messages map[string]map[string]any
+}
+
+// NewCatalog returns an empty catalog with the given fallback locale.
+func NewCatalog(fallback string) *Catalog {
+	return &Catalog{
+		Fallback: fallback,
+		messages: map[string]map[string]any{},
+	}
+}
+
+// Add adds nested messages for the locale. Keys of nested objects are
+// joined with dots, so {"messages": {"welcome": "Hi"}} is available as
+// "messages.welcome".
+func (c *Catalog) Add(locale string, messages map[string]any) error {
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[string]any{}
+	}
+
+	return flatten(c.messages[locale], "", messages)
+}
+
+// Load adds messages from JSON and YAML files in the dir of fsys.
+// A file name is the locale, like "en.json" or "uk.yaml". Files inside
+// of a locale directory use their name as a key prefix, so the "welcome"
+// message of "en/messages.json" is available as "messages.welcome".
+// YAML files can contain only nested objects with strings.
+func (c *Catalog) Load(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		ext := path.Ext(p)
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		rel := strings.TrimPrefix(strings.TrimSuffix(p, ext), dir+"/")
+		locale, prefix, _ := strings.Cut(rel, "/")
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		messages, err := decode(ext, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+
+		if prefix != "" {
+			messages = map[string]any{strings.ReplaceAll(prefix, "/", "."): messages}
+		}
+
+		if err := c.Add(locale, messages); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+
+		return nil
+	})
+}
+
+// Translate returns the message for the locale. When the message is
+// missing, it tries the base language, like "en" for "en-US", and then
+// the fallback locale.
+func (c *Catalog) Translate(locale, key string, params map[string]any) (string, bool) {
+	locales := []string{locale, baseLocale(locale), c.Fallback, baseLocale(c.Fallback)}
+	for _, loc := range locales {
+		msg, ok := c.messages[loc][key]
+		if !ok {
+			continue
+		}
+
+		if forms, ok := msg.(map[string]string); ok {
+			return replaceParams(pluralForm(loc, forms, params), params), true
+		}
+
+		return replaceParams(msg.(string), params), true
+	}
+
+	return "", false
+}
+
+func flatten(dst map[string]any, prefix string, messages map[string]any) error {
+	for key, msg := range messages {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch msg := msg.(type) {
+		case string:
+			dst[key] = msg
+		case map[string]any:
+			if forms, ok := pluralForms(msg); ok {
+				dst[key] = forms
+				continue
+			}
+
+			if err := flatten(dst, key, msg); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q must be a string or an object, got %T", key, msg)
+		}
+	}
+
+	return nil
+}
+
+// pluralForms returns forms when all keys of the object are
+// plural categories or exact matches and "other" is present.
+func pluralForms(msg map[string]any) (map[string]string, bool) {
+	if _, ok := msg[string(Other)]; !ok {
+		return nil, false
+	}
+
+	forms := make(map[string]string, len(msg))
+	for key, form := range msg {
+		str, ok := form.(string)
+		if !ok || (!isCategory(key) && !strings.HasPrefix(key, "=")) {
+			return nil, false
+		}
+		forms[key] = str
+	}
+
+	return forms, true
+}
+
+func pluralForm(locale string, forms map[string]string, params map[string]any) string {
+	count, ok := toFloat(params["count"])
+	if !ok {
+		return forms[string(Other)]
+	}
+
+	exact := "=" + strconv.FormatFloat(count, 'f', -1, 64)
+	if form, ok := forms[exact]; ok {
+		return form
+	}
+
+	if form, ok := forms[string(PluralCategory(locale, count))]; ok {
+		return form
+	}
+
+	return forms[string(Other)]
+}
+
+func replaceParams(msg string, params map[string]any) string {
+	if len(params) == 0 || !strings.Contains(msg, "{") {
+		return msg
+	}
+
+	pairs := make([]string, 0, len(params)*2)
+	for name, val := range params {
+		if val == nil {
+			val = ""
+		}
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(val))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+func baseLocale(locale string) string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return base
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestPluralCategory(t *testing.T) {
+	cases := []struct {
+		id     uint
+		locale string
+		n      float64
+		expect Category
+	}{
+		{1, "en", 1, One},
+		{2, "en", 0, Other},
+		{3, "en-US", 2, Other},
+		{4, "en", 1.5, Other},
+		{5, "fr", 0, One},
+		{6, "fr", 1.5, One},
+		{7, "uk", 1, One},
+		{8, "uk", 21, One},
+		{9, "uk", 11, Many},
+		{10, "uk", 3, Few},
+		{11, "uk", 13, Many},
+		{12, "uk_UA", 5, Many},
+		{13, "pl", 1, One},
+		{14, "pl", 21, Many},
+		{15, "pl", 22, Few},
+		{16, "cs", 3, Few},
+		{17, "cs", 5, Other},
+		{18, "ja", 1, Other},
+		{19, "ar", 0, Zero},
+		{20, "ar", 2, Two},
+		{21, "ar", 105, Few},
+		{22, "ar", 111, Many},
+	}
+
+	for _, tc := range cases {
+		if got := PluralCategory(tc.locale, tc.n); got != tc.expect {
+			t.Errorf("Case: %d. Expect %q, got %q", tc.id, tc.expect, got)
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"lang/en.json": {Data: []byte(`{
+			"welcome": "Hello, {name}!",
+			"cart": {
+				"items": {"=0": "Cart is empty", "one": "{count} item", "other": "{count} items"}
+			}
+		}`)},
+		"lang/uk.yaml": {Data: []byte(`# Ukrainian
+cart:
+  items:
+    one: "{count} товар"
+    few: '{count} товари'
+    many: "{count} товарів" # comment
+    other: "{count} товару"
+`)},
+		"lang/uk/messages.yml": {Data: []byte("welcome: Привіт, {name}! # greeting\n")},
+	}
+
+	c := NewCatalog("en")
+	if err := c.Load(fsys, "lang"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cases := []struct {
+		id     uint
+		locale string
+		key    string
+		params map[string]any
+		expect string
+	}{
+		{1, "en", "welcome", map[string]any{"name": "Anna"}, "Hello, Anna!"},
+		{2, "en", "cart.items", map[string]any{"count": 0}, "Cart is empty"},
+		{3, "en", "cart.items", map[string]any{"count": int64(1)}, "1 item"},
+		{4, "en", "cart.items", map[string]any{"count": 2.5}, "2.5 items"},
+		{5, "uk", "cart.items", map[string]any{"count": 3}, "3 товари"},
+		{6, "uk", "cart.items", map[string]any{"count": 5}, "5 товарів"},
+		{7, "uk-UA", "cart.items", map[string]any{"count": 21}, "21 товар"},
+		{8, "uk", "messages.welcome", map[string]any{"name": "Anna"}, "Привіт, Anna!"},
+		{9, "uk", "welcome", map[string]any{"name": "Anna"}, "Hello, Anna!"},
+		{10, "de", "cart.items", nil, "{count} items"},
+	}
+
+	for _, tc := range cases {
+		got, ok := c.Translate(tc.locale, tc.key, tc.params)
+		if !ok {
+			t.Fatalf("Case: %d. message %q not found", tc.id, tc.key)
+		}
+
+		if got != tc.expect {
+			t.Errorf("Case: %d. Expect %q, got %q", tc.id, tc.expect, got)
+		}
+	}
+
+	if _, ok := c.Translate("en", "missing", nil); ok {
+		t.Errorf("expected missing message not to be found")
+	}
+}
+
+func TestCatalogLoadErrors(t *testing.T) {
+	cases := []struct {
+		id   uint
+		data string
+	}{
+		{1, "items:\n  - one\n"},
+		{2, "text: |\n  line\n"},
+		{3, "key: \"unterminated\n"},
+		{4, "just text\n"},
+		{5, ": oops\n"},
+		{6, "\"\": oops\n"},
+		{7, "messages:\n  :\n"},
+		{8, "key: ''\nother: #comment\n"},
+		{9, "'': ''\n"},
+	}
+
+	for _, tc := range cases {
+		fsys := fstest.MapFS{"en.yaml": {Data: []byte(tc.data)}}
+		if err := NewCatalog("en").Load(fsys, "."); err == nil {
+			t.Errorf("Case: %d. expected an error", tc.id)
+		}
+	}
+}
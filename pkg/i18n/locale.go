@@ -0,0 +1,190 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale holds rules for formatting numbers, currencies and dates
+// in a language.
+type Locale struct {
+	// Name is the language of the locale, like "en" or "uk".
+	Name string
+
+	// Decimal separates the fraction of a number, like "." in "1.5".
+	Decimal string
+
+	// Group separates groups of thousands, like "," in "1,000".
+	Group string
+
+	// Currency is a pattern where "¤" is the currency symbol
+	// and "#" is the number, like "¤#" or "# ¤".
+	Currency string
+
+	// Months are month names in the form used in long dates.
+	Months [12]string
+
+	// ShortDate, LongDate and Time are Go layouts of named date formats.
+	// "{month}" in them is replaced with the month name.
+	ShortDate string
+	LongDate  string
+	Time      string
+
+	// Now is used by RelativeTime for less than 10 seconds.
+	Now string
+
+	// Past and Future are patterns of relative times where "{0}" is
+	// the duration, like "{0} ago" and "in {0}".
+	Past   string
+	Future string
+
+	// Units are plural forms of "second", "minute", "hour", "day",
+	// "week", "month" and "year" where "{n}" is the number.
+	Units map[string]map[Category]string
+}
+
+// LookupLocale returns formatting rules of the locale, like "en-US" or
+// "uk". It falls back to the base language and then to English.
+func LookupLocale(locale string) *Locale {
+	if l, ok := locales[locale]; ok {
+		return l
+	}
+
+	if l, ok := locales[baseLocale(locale)]; ok {
+		return l
+	}
+
+	return locales["en"]
+}
+
+// FormatInt formats the integer with group separators, like "1,234".
+func (l *Locale) FormatInt(n int64) string {
+	digits, negative := strings.CutPrefix(strconv.FormatInt(n, 10), "-")
+	return l.groupDigits(digits, "", false, negative)
+}
+
+// FormatNumber formats the number with group separators and the given
+// count of decimals. When decimals is negative, as many decimals are
+// used as needed to represent the number.
+func (l *Locale) FormatNumber(n float64, decimals int) string {
+	num := strconv.FormatFloat(math.Abs(n), 'f', decimals, 64)
+	intPart, fraction, hasFraction := strings.Cut(num, ".")
+	return l.groupDigits(intPart, fraction, hasFraction, n < 0)
+}
+
+// groupDigits joins the integer part, split into groups of thousands,
+// with the fraction using separators of the locale.
+func (l *Locale) groupDigits(intPart, fraction string, hasFraction, negative bool) string {
+	var out strings.Builder
+	if negative {
+		out.WriteByte('-')
+	}
+
+	for i := range len(intPart) {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			out.WriteString(l.Group)
+		}
+		out.WriteByte(intPart[i])
+	}
+
+	if hasFraction {
+		out.WriteString(l.Decimal + fraction)
+	}
+
+	return out.String()
+}
+
+// FormatCurrency formats the amount in the currency with the ISO 4217
+// code, like "€1,234.50" for "EUR" in English. Unknown codes are used
+// as symbols.
+func (l *Locale) FormatCurrency(amount float64, code string) string {
+	code = strings.ToUpper(code)
+
+	symbol, ok := currencySymbols[code]
+	if !ok {
+		symbol = code
+	}
+
+	decimals := 2
+	if d, ok := currencyDecimals[code]; ok {
+		decimals = d
+	}
+
+	out := strings.Replace(l.Currency, "#", l.FormatNumber(math.Abs(amount), decimals), 1)
+	out = strings.Replace(out, "¤", symbol, 1)
+
+	if amount < 0 {
+		return "-" + out
+	}
+
+	return out
+}
+
+// FormatDate formats the time with a named format or a Go layout.
+// Named formats are "short", "long", "time", "datetime" and "iso".
+func (l *Locale) FormatDate(t time.Time, format string) string {
+	switch format {
+	case "short":
+		format = l.ShortDate
+	case "long":
+		format = l.LongDate
+	case "time":
+		format = l.Time
+	case "datetime":
+		format = l.ShortDate + " " + l.Time
+	case "iso":
+		format = time.RFC3339
+	}
+
+	out := t.Format(format)
+
+	return strings.ReplaceAll(out, "{month}", l.Months[t.Month()-1])
+}
+
+// RelativeTime describes how long ago or in how long the time is
+// relative to now, like "3 minutes ago" or "in 2 days".
+func (l *Locale) RelativeTime(t, now time.Time) string {
+	diff := now.Sub(t)
+	pattern := l.Past
+	if diff < 0 {
+		diff = -diff
+		pattern = l.Future
+	}
+
+	seconds := int64(diff / time.Second)
+	days := seconds / 86400
+
+	var unit string
+	var n int64
+
+	switch {
+	case seconds < 10:
+		return l.Now
+	case seconds < 60:
+		unit, n = "second", seconds
+	case seconds < 3600:
+		unit, n = "minute", seconds/60
+	case seconds < 86400:
+		unit, n = "hour", seconds/3600
+	case days < 7:
+		unit, n = "day", days
+	case days < 30:
+		unit, n = "week", days/7
+	case days < 365:
+		unit, n = "month", days/30
+	default:
+		unit, n = "year", days/365
+	}
+
+	forms := l.Units[unit]
+	form, ok := forms[PluralCategory(l.Name, float64(n))]
+	if !ok {
+		form = forms[Other]
+	}
+
+	form = strings.ReplaceAll(form, "{n}", strconv.FormatInt(n, 10))
+
+	return strings.ReplaceAll(pattern, "{0}", form)
+}
@@ -0,0 +1,190 @@
+package i18n
+
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"UAH": "₴",
+	"PLN": "zł",
+	"CZK": "Kč",
+	"CHF": "CHF",
+	"CAD": "CA$",
+	"AUD": "A$",
+	"INR": "₹",
+	"KRW": "₩",
+	"BRL": "R$",
+	"SEK": "kr",
+	"NOK": "kr",
+	"DKK": "kr",
+}
+
+var currencyDecimals = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// forms returns plural forms for languages with "one" and "other".
+func forms(one, other string) map[Category]string {
+	return map[Category]string{One: one, Other: other}
+}
+
+// slavicForms returns plural forms for languages
+// with "one", "few" and "many".
+func slavicForms(one, few, many string) map[Category]string {
+	return map[Category]string{One: one, Few: few, Many: many, Other: few}
+}
+
+var locales = map[string]*Locale{
+	"en": {
+		Name:     "en",
+		Decimal:  ".",
+		Group:    ",",
+		Currency: "¤#",
+		Months: [12]string{
+			"January", "February", "March", "April", "May", "June", "July",
+			"August", "September", "October", "November", "December",
+		},
+		ShortDate: "01/02/2006",
+		LongDate:  "{month} 2, 2006",
+		Time:      "3:04 PM",
+		Now:       "just now",
+		Past:      "{0} ago",
+		Future:    "in {0}",
+		Units: map[string]map[Category]string{
+			"second": forms("{n} second", "{n} seconds"),
+			"minute": forms("{n} minute", "{n} minutes"),
+			"hour":   forms("{n} hour", "{n} hours"),
+			"day":    forms("{n} day", "{n} days"),
+			"week":   forms("{n} week", "{n} weeks"),
+			"month":  forms("{n} month", "{n} months"),
+			"year":   forms("{n} year", "{n} years"),
+		},
+	},
+	"de": {
+		Name:     "de",
+		Decimal:  ",",
+		Group:    ".",
+		Currency: "#\u00a0¤",
+		Months: [12]string{
+			"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli",
+			"August", "September", "Oktober", "November", "Dezember",
+		},
+		ShortDate: "02.01.2006",
+		LongDate:  "2. {month} 2006",
+		Time:      "15:04",
+		Now:       "gerade eben",
+		Past:      "vor {0}",
+		Future:    "in {0}",
+		Units: map[string]map[Category]string{
+			"second": forms("{n} Sekunde", "{n} Sekunden"),
+			"minute": forms("{n} Minute", "{n} Minuten"),
+			"hour":   forms("{n} Stunde", "{n} Stunden"),
+			"day":    forms("{n} Tag", "{n} Tagen"),
+			"week":   forms("{n} Woche", "{n} Wochen"),
+			"month":  forms("{n} Monat", "{n} Monaten"),
+			"year":   forms("{n} Jahr", "{n} Jahren"),
+		},
+	},
+	"fr": {
+		Name:     "fr",
+		Decimal:  ",",
+		Group:    "\u202f",
+		Currency: "#\u00a0¤",
+		Months: [12]string{
+			"janvier", "février", "mars", "avril", "mai", "juin", "juillet",
+			"août", "septembre", "octobre", "novembre", "décembre",
+		},
+		ShortDate: "02/01/2006",
+		LongDate:  "2 {month} 2006",
+		Time:      "15:04",
+		Now:       "à l’instant",
+		Past:      "il y a {0}",
+		Future:    "dans {0}",
+		Units: map[string]map[Category]string{
+			"second": forms("{n} seconde", "{n} secondes"),
+			"minute": forms("{n} minute", "{n} minutes"),
+			"hour":   forms("{n} heure", "{n} heures"),
+			"day":    forms("{n} jour", "{n} jours"),
+			"week":   forms("{n} semaine", "{n} semaines"),
+			"month":  forms("{n} mois", "{n} mois"),
+			"year":   forms("{n} an", "{n} ans"),
+		},
+	},
+	"es": {
+		Name:     "es",
+		Decimal:  ",",
+		Group:    ".",
+		Currency: "#\u00a0¤",
+		Months: [12]string{
+			"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio",
+			"agosto", "septiembre", "octubre", "noviembre", "diciembre",
+		},
+		ShortDate: "02/01/2006",
+		LongDate:  "2 de {month} de 2006",
+		Time:      "15:04",
+		Now:       "ahora mismo",
+		Past:      "hace {0}",
+		Future:    "dentro de {0}",
+		Units: map[string]map[Category]string{
+			"second": forms("{n} segundo", "{n} segundos"),
+			"minute": forms("{n} minuto", "{n} minutos"),
+			"hour":   forms("{n} hora", "{n} horas"),
+			"day":    forms("{n} día", "{n} días"),
+			"week":   forms("{n} semana", "{n} semanas"),
+			"month":  forms("{n} mes", "{n} meses"),
+			"year":   forms("{n} año", "{n} años"),
+		},
+	},
+	"uk": {
+		Name:     "uk",
+		Decimal:  ",",
+		Group:    "\u00a0",
+		Currency: "#\u00a0¤",
+		Months: [12]string{
+			"січня", "лютого", "березня", "квітня", "травня", "червня", "липня",
+			"серпня", "вересня", "жовтня", "листопада", "грудня",
+		},
+		ShortDate: "02.01.2006",
+		LongDate:  "2 {month} 2006 р.",
+		Time:      "15:04",
+		Now:       "щойно",
+		Past:      "{0} тому",
+		Future:    "через {0}",
+		Units: map[string]map[Category]string{
+			"second": slavicForms("{n} секунду", "{n} секунди", "{n} секунд"),
+			"minute": slavicForms("{n} хвилину", "{n} хвилини", "{n} хвилин"),
+			"hour":   slavicForms("{n} годину", "{n} години", "{n} годин"),
+			"day":    slavicForms("{n} день", "{n} дні", "{n} днів"),
+			"week":   slavicForms("{n} тиждень", "{n} тижні", "{n} тижнів"),
+			"month":  slavicForms("{n} місяць", "{n} місяці", "{n} місяців"),
+			"year":   slavicForms("{n} рік", "{n} роки", "{n} років"),
+		},
+	},
+	"pl": {
+		Name:     "pl",
+		Decimal:  ",",
+		Group:    "\u00a0",
+		Currency: "#\u00a0¤",
+		Months: [12]string{
+			"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca",
+			"sierpnia", "września", "października", "listopada", "grudnia",
+		},
+		ShortDate: "02.01.2006",
+		LongDate:  "2 {month} 2006",
+		Time:      "15:04",
+		Now:       "przed chwilą",
+		Past:      "{0} temu",
+		Future:    "za {0}",
+		Units: map[string]map[Category]string{
+			"second": slavicForms("{n} sekundę", "{n} sekundy", "{n} sekund"),
+			"minute": slavicForms("{n} minutę", "{n} minuty", "{n} minut"),
+			"hour":   slavicForms("{n} godzinę", "{n} godziny", "{n} godzin"),
+			"day":    slavicForms("{n} dzień", "{n} dni", "{n} dni"),
+			"week":   slavicForms("{n} tydzień", "{n} tygodnie", "{n} tygodni"),
+			"month":  slavicForms("{n} miesiąc", "{n} miesiące", "{n} miesięcy"),
+			"year":   slavicForms("{n} rok", "{n} lata", "{n} lat"),
+		},
+	},
+}
@@ -0,0 +1,106 @@
+package i18n
+
+import "math"
+
+// Category is a CLDR plural category.
+type Category string
+
+const (
+	Zero  Category = "zero"
+	One   Category = "one"
+	Two   Category = "two"
+	Few   Category = "few"
+	Many  Category = "many"
+	Other Category = "other"
+)
+
+func isCategory(s string) bool {
+	switch Category(s) {
+	case Zero, One, Two, Few, Many, Other:
+		return true
+	}
+	return false
+}
+
+// PluralCategory returns the CLDR plural category of n in the locale,
+// like One for 1 in "en" or Few for 3 in "uk". Unknown languages use
+// the English rules.
+func PluralCategory(locale string, n float64) Category {
+	i := int64(math.Abs(n))
+	isInt := n == math.Trunc(n)
+
+	switch baseLocale(locale) {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my":
+		return Other
+	case "fr", "pt", "hy", "ff", "kab":
+		if i == 0 || i == 1 {
+			return One
+		}
+	case "ru", "uk", "be", "sr", "hr", "bs":
+		if !isInt {
+			return Other
+		}
+		return slavicCategory(i)
+	case "pl":
+		if !isInt {
+			return Other
+		}
+		if i == 1 {
+			return One
+		}
+		if c := slavicCategory(i); c == Few {
+			return Few
+		}
+		return Many
+	case "cs", "sk":
+		switch {
+		case !isInt:
+			return Many
+		case i == 1:
+			return One
+		case i >= 2 && i <= 4:
+			return Few
+		}
+	case "ar":
+		switch {
+		case !isInt:
+			return Other
+		case i == 0:
+			return Zero
+		case i == 1:
+			return One
+		case i == 2:
+			return Two
+		case i%100 >= 3 && i%100 <= 10:
+			return Few
+		case i%100 >= 11:
+			return Many
+		}
+	case "he":
+		switch {
+		case isInt && i == 1:
+			return One
+		case isInt && i == 2:
+			return Two
+		}
+	default:
+		if isInt && i == 1 {
+			return One
+		}
+	}
+
+	return Other
+}
+
+// slavicCategory is the rule shared by East Slavic
+// and most South Slavic languages.
+func slavicCategory(i int64) Category {
+	switch {
+	case i%10 == 1 && i%100 != 11:
+		return One
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
@@ -13,6 +13,7 @@ var simpleTokens = map[byte]token.TokenType{
 	'?': token.QUESTION,
 	'/': token.DIV,
 	'%': token.MOD,
+	'~': token.CONCAT,
 	',': token.COMMA,
 	'[': token.LBRACKET,
 	']': token.RBRACKET,
@@ -70,6 +71,17 @@ type Lexer struct {
 	// We increment it when we find "{" and decrement when we find "}".
 	// It helps to determine if we are in text or Textwire.
 	countCurlyBraces int
+
+	// trimNext determines if leading whitespace of the next text token
+	// should be removed. It's set by "-}}" and by "~" after a directive.
+	trimNext bool
+
+	// trimLines determines if lines that contain only directives should
+	// be removed from the output. See TrimDirectiveLines.
+	trimLines bool
+
+	// buffer holds tokens that are already read when trimLines is set.
+	buffer []token.Token
 }
 
 func New(input string) *Lexer {
@@ -85,7 +97,50 @@ func New(input string) *Lexer {
 	return l
 }
 
+// TrimDirectiveLines makes the lexer remove lines that contain nothing but
+// directives, like "@if(x)" or "@end", together with their indentation and
+// line break. It must be called before the first Next call.
+func (l *Lexer) TrimDirectiveLines() {
+	l.trimLines = true
+}
+
+// TrimsDirectiveLines reports whether TrimDirectiveLines was called.
+func (l *Lexer) TrimsDirectiveLines() bool {
+	return l.trimLines
+}
+
 func (l *Lexer) Next() token.Token {
+	if !l.trimLines {
+		return l.next()
+	}
+
+	if l.buffer == nil {
+		l.buffer = trimLines(l.input, l.readAll())
+	}
+
+	tok := l.buffer[0]
+	if len(l.buffer) > 1 {
+		l.buffer = l.buffer[1:]
+	}
+
+	return tok
+}
+
+func (l *Lexer) readAll() []token.Token {
+	var tokens []token.Token
+	for {
+		tok := l.next()
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			return tokens
+		}
+	}
+}
+
+func (l *Lexer) next() token.Token {
+	trimLeft := l.trimNext
+	l.trimNext = false
+
 	if !l.isText {
 		l.skipWhitespace()
 	}
@@ -97,17 +152,34 @@ func (l *Lexer) Next() token.Token {
 
 	if l.startsWith('{', '{', '-', '-') {
 		l.skipComment()
-		return l.Next()
+		return l.next()
+	}
+
+	if l.isTrimLeftBraces() {
+		tok := l.bracesToken(token.LBRACES, "{{")
+		l.readChar() // skip "-"
+		return tok
 	}
 
 	if l.startsWith('{', '{') {
 		return l.bracesToken(token.LBRACES, "{{")
 	}
 
+	if !l.isText && l.startsWith('-', '}', '}') && l.countCurlyBraces == 0 {
+		l.readChar() // skip "-"
+		l.trimNext = true
+		return l.bracesToken(token.RBRACES, "}}")
+	}
+
 	if l.startsWith('}', '}') && l.countCurlyBraces == 0 {
 		return l.bracesToken(token.RBRACES, "}}")
 	}
 
+	if l.isTrimDirective() {
+		l.readChar() // skip "~"
+		return l.directiveToken()
+	}
+
 	if l.isDirectiveToken() {
 		return l.directiveToken()
 	}
@@ -116,7 +188,47 @@ func (l *Lexer) Next() token.Token {
 		return l.embeddedCodeToken()
 	}
 
-	return l.newToken(token.TEXT, l.readText())
+	text := l.readText()
+	if trimLeft {
+		text = strings.TrimLeft(text, " \t\r\n")
+	}
+
+	if text == "" {
+		return l.next()
+	}
+
+	return l.newToken(token.TEXT, text)
+}
+
+// isTrimLeftBraces checks if the current position starts "{{-" followed
+// by whitespace, which trims whitespace before the braces.
+func (l *Lexer) isTrimLeftBraces() bool {
+	return l.prevChar() != '\\' &&
+		l.startsWith('{', '{', '-') &&
+		l.isWhitespace(l.charAt(l.pos+3))
+}
+
+// isTrimDirective checks if the current position is "~" followed by
+// a directive, which trims whitespace before the directive.
+func (l *Lexer) isTrimDirective() bool {
+	return l.char == '~' &&
+		l.prevChar() != '\\' &&
+		l.hasDirectivePrefixAt(l.pos+1)
+}
+
+func (l *Lexer) isEscapedTrimDirective() bool {
+	return l.char == '~' &&
+		l.prevChar() == '\\' &&
+		l.hasDirectivePrefixAt(l.pos+1)
+}
+
+// skipTrimRight skips "~" after a directive and makes the lexer
+// trim whitespace of the following text.
+func (l *Lexer) skipTrimRight() {
+	if l.char == '~' {
+		l.readChar()
+		l.trimNext = true
+	}
 }
 
 func (l *Lexer) startsWith(chars ...byte) bool {
@@ -167,7 +279,12 @@ func (l *Lexer) directiveToken() token.Token {
 	l.isDirective = l.char == '(' || l.nextNonSpaceIs('(')
 	l.isText = !l.isDirective
 
-	return l.newToken(tok, keyword)
+	result := l.newToken(tok, keyword)
+	if !l.isDirective {
+		l.skipTrimRight()
+	}
+
+	return result
 }
 
 func (l *Lexer) embeddedCodeToken() token.Token {
@@ -211,6 +328,9 @@ func (l *Lexer) embeddedCodeToken() token.Token {
 	case '+':
 		return l.operatorToken('+', token.INC, token.ADD, "++", "+")
 	case '=':
+		if l.peek(0) == '>' {
+			return l.twoCharToken(token.ARROW, "=>")
+		}
 		return l.operatorToken('=', token.EQ, token.ASSIGN, "==", "=")
 	}
 
@@ -268,7 +388,8 @@ func (l *Lexer) rightParenthesesToken() token.Token {
 		l.countDirectiveParentheses -= 1
 	}
 
-	if l.isDirective && l.countDirectiveParentheses == 0 {
+	closesDirective := l.isDirective && l.countDirectiveParentheses == 0
+	if closesDirective {
 		l.isDirective = false
 		l.isText = true
 	}
@@ -276,7 +397,12 @@ func (l *Lexer) rightParenthesesToken() token.Token {
 	l.tokenBegins()
 	l.readChar() // skip ")"
 
-	return l.newToken(token.RPAREN, ")")
+	result := l.newToken(token.RPAREN, ")")
+	if closesDirective {
+		l.skipTrimRight()
+	}
+
+	return result
 }
 
 func (l *Lexer) twoCharToken(tokType token.TokenType, literal string) token.Token {
@@ -357,11 +483,14 @@ func (l *Lexer) readDirective() (token.TokenType, string) {
 }
 
 func (l *Lexer) hasDirectivePrefix() bool {
-	if l.char != '@' {
+	return l.hasDirectivePrefixAt(l.pos)
+}
+
+func (l *Lexer) hasDirectivePrefixAt(pos int) bool {
+	if l.charAt(pos) != '@' {
 		return false
 	}
 
-	pos := l.pos
 	longestDir := token.LongestDirective()
 
 	for i := 1; i <= longestDir; i++ {
@@ -482,13 +611,17 @@ func (l *Lexer) readText() string {
 	l.tokenBegins()
 
 	for l.isText && l.char != 0 {
+		if l.isTrimLeftBraces() || l.isTrimDirective() {
+			return strings.TrimRight(out.String(), " \t\r\n")
+		}
+
 		areBraces, escapedBraces := l.areBracesToken()
 
 		if areBraces || l.isDirectiveToken() {
 			break
 		}
 
-		if escapedBraces || l.isEscapedDirective() {
+		if escapedBraces || l.isEscapedDirective() || l.isEscapedTrimDirective() {
 			out.Truncate(out.Len() - 1)
 		}
 
@@ -499,6 +632,15 @@ func (l *Lexer) readText() string {
 	return out.String()
 }
 
+// charAt returns the character at the given position of the input
+// or 0 if the position is out of range.
+func (l *Lexer) charAt(pos int) byte {
+	if pos < 0 || pos >= len(l.input) {
+		return 0
+	}
+	return l.input[pos]
+}
+
 func (l *Lexer) prevChar() byte {
 	if l.pos > 0 {
 		return l.input[l.pos-1]
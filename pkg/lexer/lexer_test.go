@@ -152,6 +152,28 @@ func TestIfStmt(t *testing.T) {
 	})
 }
 
+func TestSwitchDirective(t *testing.T) {
+	inp := `@switch(x)@case('a', 1)A@defaultB@end`
+
+	TokenizeString(t, inp, []token.Token{
+		{Type: token.SWITCH, Lit: "@switch", Pos: &position.Pos{EndCol: 6}},
+		{Type: token.LPAREN, Lit: "(", Pos: &position.Pos{StartCol: 7, EndCol: 7}},
+		{Type: token.IDENT, Lit: "x", Pos: &position.Pos{StartCol: 8, EndCol: 8}},
+		{Type: token.RPAREN, Lit: ")", Pos: &position.Pos{StartCol: 9, EndCol: 9}},
+		{Type: token.CASE, Lit: "@case", Pos: &position.Pos{StartCol: 10, EndCol: 14}},
+		{Type: token.LPAREN, Lit: "(", Pos: &position.Pos{StartCol: 15, EndCol: 15}},
+		{Type: token.STR, Lit: "a", Pos: &position.Pos{StartCol: 16, EndCol: 18}},
+		{Type: token.COMMA, Lit: ",", Pos: &position.Pos{StartCol: 19, EndCol: 19}},
+		{Type: token.INT, Lit: "1", Pos: &position.Pos{StartCol: 21, EndCol: 21}},
+		{Type: token.RPAREN, Lit: ")", Pos: &position.Pos{StartCol: 22, EndCol: 22}},
+		{Type: token.TEXT, Lit: "A", Pos: &position.Pos{StartCol: 23, EndCol: 23}},
+		{Type: token.DEFAULT, Lit: "@default", Pos: &position.Pos{StartCol: 24, EndCol: 31}},
+		{Type: token.TEXT, Lit: "B", Pos: &position.Pos{StartCol: 32, EndCol: 32}},
+		{Type: token.END, Lit: "@end", Pos: &position.Pos{StartCol: 33, EndCol: 36}},
+		{Type: token.EOF, Lit: "", Pos: &position.Pos{StartCol: 37, EndCol: 37}},
+	})
+}
+
 func TestUseStmt(t *testing.T) {
 	inp := `<div>@use("layouts/main")</div>`
 
@@ -226,6 +248,36 @@ func TestOperators(t *testing.T) {
 	})
 }
 
+func TestConcatOperator(t *testing.T) {
+	inp := "{{ 'a' ~ 1 }}"
+
+	TokenizeString(t, inp, []token.Token{
+		{Type: token.LBRACES, Lit: "{{", Pos: &position.Pos{EndCol: 1}},
+		{Type: token.STR, Lit: "a", Pos: &position.Pos{StartCol: 3, EndCol: 5}},
+		{Type: token.CONCAT, Lit: "~", Pos: &position.Pos{StartCol: 7, EndCol: 7}},
+		{Type: token.INT, Lit: "1", Pos: &position.Pos{StartCol: 9, EndCol: 9}},
+		{Type: token.RBRACES, Lit: "}}", Pos: &position.Pos{StartCol: 11, EndCol: 12}},
+		{Type: token.EOF, Lit: "", Pos: &position.Pos{StartCol: 13, EndCol: 13}},
+	})
+}
+
+func TestArrowFunction(t *testing.T) {
+	inp := "{{ u => u.age >= 18 }}"
+
+	TokenizeString(t, inp, []token.Token{
+		{Type: token.LBRACES, Lit: "{{", Pos: &position.Pos{EndCol: 1}},
+		{Type: token.IDENT, Lit: "u", Pos: &position.Pos{StartCol: 3, EndCol: 3}},
+		{Type: token.ARROW, Lit: "=>", Pos: &position.Pos{StartCol: 5, EndCol: 6}},
+		{Type: token.IDENT, Lit: "u", Pos: &position.Pos{StartCol: 8, EndCol: 8}},
+		{Type: token.DOT, Lit: ".", Pos: &position.Pos{StartCol: 9, EndCol: 9}},
+		{Type: token.IDENT, Lit: "age", Pos: &position.Pos{StartCol: 10, EndCol: 12}},
+		{Type: token.GTHAN_EQ, Lit: ">=", Pos: &position.Pos{StartCol: 14, EndCol: 15}},
+		{Type: token.INT, Lit: "18", Pos: &position.Pos{StartCol: 17, EndCol: 18}},
+		{Type: token.RBRACES, Lit: "}}", Pos: &position.Pos{StartCol: 20, EndCol: 21}},
+		{Type: token.EOF, Lit: "", Pos: &position.Pos{StartCol: 22, EndCol: 22}},
+	})
+}
+
 func TestStrings(test *testing.T) {
 	test.Run("String with quotes", func(t *testing.T) {
 		inp := `{{ "Anna \"and\" Serhii" + '' }}`
@@ -859,3 +911,89 @@ func TestPassifDirective(t *testing.T) {
 		{Type: token.EOF, Lit: "", Pos: &position.Pos{StartCol: 21, EndCol: 21}},
 	})
 }
+
+func TestTrimMarkers(t *testing.T) {
+	t.Run("Embedded code", func(t *testing.T) {
+		inp := "<b> {{- x -}}\n</b>"
+
+		TokenizeString(t, inp, []token.Token{
+			{Type: token.TEXT, Lit: "<b>", Pos: &position.Pos{EndCol: 3}},
+			{Type: token.LBRACES, Lit: "{{", Pos: &position.Pos{StartCol: 4, EndCol: 5}},
+			{Type: token.IDENT, Lit: "x", Pos: &position.Pos{StartCol: 8, EndCol: 8}},
+			{Type: token.RBRACES, Lit: "}}", Pos: &position.Pos{StartCol: 11, EndCol: 12}},
+			{
+				Type: token.TEXT,
+				Lit:  "</b>",
+				Pos:  &position.Pos{StartCol: 13, EndCol: 3, EndLine: 1},
+			},
+			{
+				Type: token.EOF,
+				Lit:  "",
+				Pos:  &position.Pos{StartCol: 4, EndCol: 4, StartLine: 1, EndLine: 1},
+			},
+		})
+	})
+
+	t.Run("Negative number and decrement", func(t *testing.T) {
+		inp := "{{-1}}{{ a--}}"
+
+		TokenizeString(t, inp, []token.Token{
+			{Type: token.LBRACES, Lit: "{{", Pos: &position.Pos{EndCol: 1}},
+			{Type: token.SUB, Lit: "-", Pos: &position.Pos{StartCol: 2, EndCol: 2}},
+			{Type: token.INT, Lit: "1", Pos: &position.Pos{StartCol: 3, EndCol: 3}},
+			{Type: token.RBRACES, Lit: "}}", Pos: &position.Pos{StartCol: 4, EndCol: 5}},
+			{Type: token.LBRACES, Lit: "{{", Pos: &position.Pos{StartCol: 6, EndCol: 7}},
+			{Type: token.IDENT, Lit: "a", Pos: &position.Pos{StartCol: 9, EndCol: 9}},
+			{Type: token.DEC, Lit: "--", Pos: &position.Pos{StartCol: 10, EndCol: 11}},
+			{Type: token.RBRACES, Lit: "}}", Pos: &position.Pos{StartCol: 12, EndCol: 13}},
+		})
+	})
+
+	t.Run("Directives", func(t *testing.T) {
+		inp := "a ~@if(x)~ b ~@end~ c"
+
+		TokenizeString(t, inp, []token.Token{
+			{Type: token.TEXT, Lit: "a", Pos: &position.Pos{EndCol: 1}},
+			{Type: token.IF, Lit: "@if", Pos: &position.Pos{StartCol: 3, EndCol: 5}},
+			{Type: token.LPAREN, Lit: "(", Pos: &position.Pos{StartCol: 6, EndCol: 6}},
+			{Type: token.IDENT, Lit: "x", Pos: &position.Pos{StartCol: 7, EndCol: 7}},
+			{Type: token.RPAREN, Lit: ")", Pos: &position.Pos{StartCol: 8, EndCol: 8}},
+			{Type: token.TEXT, Lit: "b", Pos: &position.Pos{StartCol: 10, EndCol: 12}},
+			{Type: token.END, Lit: "@end", Pos: &position.Pos{StartCol: 14, EndCol: 17}},
+			{Type: token.TEXT, Lit: "c", Pos: &position.Pos{StartCol: 19, EndCol: 20}},
+			{Type: token.EOF, Lit: "", Pos: &position.Pos{StartCol: 21, EndCol: 21}},
+		})
+	})
+
+	t.Run("Escaped marker", func(t *testing.T) {
+		inp := `a \~@end`
+
+		TokenizeString(t, inp, []token.Token{
+			{Type: token.TEXT, Lit: "a ~", Pos: &position.Pos{EndCol: 3}},
+			{Type: token.END, Lit: "@end", Pos: &position.Pos{StartCol: 4, EndCol: 7}},
+		})
+	})
+}
+
+func TestTrimDirectiveLines(t *testing.T) {
+	inp := "<ul>\n  @each(x in y)\n    <li>{{ x }}</li>\n  @end\n</ul>\n@if(a) b @end"
+
+	l := New(inp)
+	l.TrimDirectiveLines()
+
+	expect := []string{
+		"<ul>\n", "@each", "(", "x", "in", "y", ")", "    <li>", "{{", "x", "}}",
+		"</li>\n", "@end", "</ul>\n", "@if", "(", "a", ")", " b ", "@end", "",
+	}
+
+	for i, lit := range expect {
+		tok := l.Next()
+		if tok.Lit != lit {
+			t.Fatalf("Case: %d. Expect literal %q, got %q", i, lit, tok.Lit)
+		}
+	}
+
+	if tok := l.Next(); tok.Type != token.EOF {
+		t.Fatalf("Expect EOF after the last token, got %q", tok.Lit)
+	}
+}
@@ -0,0 +1,84 @@
+package lexer
+
+import (
+	"strings"
+
+	"github.com/textwire/textwire/v4/pkg/token"
+)
+
+// trimLines removes indentation and the line break of every line that
+// contains only directives. Text tokens that become empty are dropped.
+func trimLines(input string, tokens []token.Token) []token.Token {
+	lines := strings.Split(input, "\n")
+
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Type == token.TEXT || tokens[i].Type == token.EOF {
+			continue
+		}
+
+		end := i
+		for end < len(tokens) && tokens[end].Type != token.TEXT &&
+			tokens[end].Type != token.EOF {
+			end++
+		}
+
+		if isDirectiveLine(lines, tokens[i:end]) {
+			if i > 0 && tokens[i-1].Type == token.TEXT {
+				tokens[i-1].Lit = strings.TrimRight(tokens[i-1].Lit, " \t")
+			}
+
+			if tokens[end].Type == token.TEXT {
+				tokens[end].Lit = trimFirstLineBreak(tokens[end].Lit)
+			}
+		}
+
+		i = end
+	}
+
+	result := tokens[:0]
+	for _, tok := range tokens {
+		if tok.Type != token.TEXT || tok.Lit != "" {
+			result = append(result, tok)
+		}
+	}
+
+	return result
+}
+
+// isDirectiveLine checks if the run of tokens starts with a directive,
+// has no embedded code and is the only content on its lines.
+func isDirectiveLine(lines []string, run []token.Token) bool {
+	if token.LookupDirective(run[0].Lit) == token.ILLEGAL {
+		return false
+	}
+
+	for _, tok := range run {
+		if tok.Type == token.LBRACES {
+			return false
+		}
+	}
+
+	first, last := run[0].Pos, run[len(run)-1].Pos
+	if int(first.StartLine) >= len(lines) || int(last.EndLine) >= len(lines) {
+		return false
+	}
+
+	before := lines[first.StartLine]
+	before = before[:min(int(first.StartCol), len(before))]
+
+	after := lines[last.EndLine]
+	after = after[min(int(last.EndCol)+1, len(after)):]
+
+	return strings.TrimSpace(before) == "" && strings.TrimSpace(after) == ""
+}
+
+func trimFirstLineBreak(text string) string {
+	rest := strings.TrimLeft(text, " \t")
+	rest = strings.TrimPrefix(rest, "\r")
+
+	if !strings.HasPrefix(rest, "\n") {
+		return text
+	}
+
+	return rest[1:]
+}
@@ -0,0 +1,195 @@
+package linker
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/textwire/textwire/v4/pkg/ast"
+	"github.com/textwire/textwire/v4/pkg/file"
+	"github.com/textwire/textwire/v4/pkg/position"
+)
+
+// WarningKind identifies the problem reported by Warning.
+type WarningKind string
+
+const (
+	WarnUnusedComponent WarningKind = "unused-component"
+	WarnUnusedLayout    WarningKind = "unused-layout"
+	WarnUnusedReserve   WarningKind = "unused-reserve"
+	WarnUnusedSlot      WarningKind = "unused-slot"
+)
+
+const (
+	msgUnusedComponent = "component '%s' is not used by any @component directive"
+	msgUnusedLayout    = "layout '%s' is not used by any @use directive"
+	msgUnusedReserve   = "@reserve('%s') in layout '%s' is never filled by @insert"
+	msgUnusedSlot      = "@slot('%s') in component '%s' never receives content from @pass"
+	msgUnusedDefSlot   = "default @slot in component '%s' never receives content"
+)
+
+// Warning describes a problem that doesn't prevent templates from
+// rendering, but is most likely a mistake, like an unused component.
+// Unlike linking errors, warnings are only reported by Audit.
+type Warning struct {
+	Kind WarningKind `json:"kind"`
+
+	// Name of the component or layout the warning is about.
+	Name string `json:"name"`
+
+	// Path is the absolute path to the file with the problem.
+	Path string `json:"path"`
+
+	Pos     *position.Pos `json:"pos"`
+	Message string        `json:"message"`
+}
+
+// String returns the warning in the same format as fail.Error.
+func (w Warning) String() string {
+	return fmt.Sprintf("[Textwire WARNING in %s:%d]: %s", w.Path, w.Pos.Line(), w.Message)
+}
+
+// Audit looks for components and layouts that are never used, reserves
+// that are never filled and slots that never receive content. Components
+// are programs inside the "components/" directory or programs with @slot
+// directives, layouts are programs inside the "layouts/" directory or
+// programs with @reserve directives. Warnings are sorted by path and position.
+func (nl *NodeLinker) Audit() []Warning {
+	inserts := map[string]map[string]bool{}
+	slots := map[string]map[string]bool{}
+
+	for _, dep := range nl.Graph() {
+		used := slots
+		names := dep.Slots
+		if dep.Kind == DepLayout {
+			used, names = inserts, dep.Inserts
+		}
+
+		if used[dep.To] == nil {
+			used[dep.To] = map[string]bool{}
+		}
+
+		for _, name := range names {
+			used[dep.To][name] = true
+		}
+	}
+
+	warnings := make([]Warning, 0, 4)
+
+	for _, prog := range nl.Programs {
+		if isLayout(prog) {
+			warnings = append(warnings, auditLayout(prog, inserts[prog.Name])...)
+		}
+
+		if isComponent(prog) {
+			warnings = append(warnings, auditComponent(prog, slots[prog.Name])...)
+		}
+	}
+
+	slices.SortFunc(warnings, func(a, b Warning) int {
+		return cmp.Or(
+			cmp.Compare(a.Path, b.Path),
+			cmp.Compare(a.Pos.StartLine, b.Pos.StartLine),
+			cmp.Compare(a.Pos.StartCol, b.Pos.StartCol),
+		)
+	})
+
+	return warnings
+}
+
+// auditLayout reports the unused layout or its unused reserves. Inserts
+// holds names of inserts passed to the layout, it's nil when layout is unused.
+func auditLayout(prog *ast.Program, inserts map[string]bool) []Warning {
+	if inserts == nil {
+		return []Warning{newWarning(WarnUnusedLayout, prog, nil, msgUnusedLayout, prog.Name)}
+	}
+
+	var warnings []Warning
+
+	for name, reserve := range prog.Reserves {
+		if inserts[name] {
+			continue
+		}
+
+		warnings = append(warnings, newWarning(
+			WarnUnusedReserve,
+			prog,
+			reserve.Pos(),
+			msgUnusedReserve,
+			name,
+			prog.Name,
+		))
+	}
+
+	return warnings
+}
+
+// auditComponent reports the unused component or its unused slots. Slots
+// holds names of passed slots, it's nil when component is unused.
+func auditComponent(prog *ast.Program, slots map[string]bool) []Warning {
+	if slots == nil {
+		return []Warning{
+			newWarning(WarnUnusedComponent, prog, nil, msgUnusedComponent, prog.Name),
+		}
+	}
+
+	var warnings []Warning
+
+	for name, slot := range prog.Slots {
+		if slots[name] {
+			continue
+		}
+
+		if name == "" {
+			warnings = append(warnings, newWarning(
+				WarnUnusedSlot,
+				prog,
+				slot.Pos(),
+				msgUnusedDefSlot,
+				prog.Name,
+			))
+			continue
+		}
+
+		warnings = append(warnings, newWarning(
+			WarnUnusedSlot,
+			prog,
+			slot.Pos(),
+			msgUnusedSlot,
+			name,
+			prog.Name,
+		))
+	}
+
+	return warnings
+}
+
+func newWarning(
+	kind WarningKind,
+	prog *ast.Program,
+	pos *position.Pos,
+	msg string,
+	args ...any,
+) Warning {
+	if pos == nil {
+		pos = &position.Pos{}
+	}
+
+	return Warning{
+		Kind:    kind,
+		Name:    prog.Name,
+		Path:    prog.AbsPath,
+		Pos:     pos,
+		Message: fmt.Sprintf(msg, args...),
+	}
+}
+
+func isLayout(prog *ast.Program) bool {
+	return prog.IsLayout || len(prog.Reserves) > 0 ||
+		strings.HasPrefix(prog.Name, string(file.PathAliasUse))
+}
+
+func isComponent(prog *ast.Program) bool {
+	return len(prog.Slots) > 0 || strings.HasPrefix(prog.Name, string(file.PathAliasComp))
+}
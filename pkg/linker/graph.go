@@ -0,0 +1,99 @@
+package linker
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/textwire/textwire/v4/pkg/ast"
+)
+
+// DepKind describes how one template depends on another.
+type DepKind string
+
+const (
+	DepLayout    DepKind = "layout"    // Template uses a layout with @use
+	DepComponent DepKind = "component" // Template renders a component with @component
+)
+
+// Dependency is an edge of the dependency graph between two templates.
+type Dependency struct {
+	// From is the name of the dependent template, like "home".
+	From string `json:"from"`
+
+	// To is the name of the layout or component, like "components/book".
+	To string `json:"to"`
+
+	Kind DepKind `json:"kind"`
+
+	// Inserts are names of @insert directives passed to the layout.
+	// Only set for DepLayout.
+	Inserts []string `json:"inserts,omitempty"`
+
+	// Slots are names of slots that get content through @pass directives.
+	// The default slot has an empty name. Only set for DepComponent.
+	Slots []string `json:"slots,omitempty"`
+}
+
+// Graph returns all dependencies between programs sorted by From, To and Kind.
+// Multiple @component directives with the same component in a single program
+// are merged into one dependency. Dependencies on missing templates are
+// included as well, since linking can fail while the file watcher is running.
+func (nl *NodeLinker) Graph() []Dependency {
+	deps := make([]Dependency, 0, len(nl.Programs))
+
+	for _, prog := range nl.Programs {
+		if prog.HasUseDir() {
+			deps = append(deps, Dependency{
+				From:    prog.Name,
+				To:      prog.UseDir.Name.Val,
+				Kind:    DepLayout,
+				Inserts: slices.Sorted(maps.Keys(prog.Inserts)),
+			})
+		}
+
+		deps = append(deps, compDependencies(prog)...)
+	}
+
+	slices.SortFunc(deps, func(a, b Dependency) int {
+		return cmp.Or(
+			cmp.Compare(a.From, b.From),
+			cmp.Compare(a.To, b.To),
+			cmp.Compare(a.Kind, b.Kind),
+		)
+	})
+
+	return deps
+}
+
+// compDependencies returns component dependencies of the program.
+func compDependencies(prog *ast.Program) []Dependency {
+	slots := map[string]map[string]struct{}{}
+
+	for _, compDir := range prog.Components {
+		name := compDir.Name.Val
+		if _, ok := slots[name]; !ok {
+			slots[name] = map[string]struct{}{}
+		}
+
+		if compDir.DefaultPass != nil {
+			slots[name][""] = struct{}{}
+		}
+
+		for _, pass := range compDir.Passes {
+			slots[name][pass.Name.Val] = struct{}{}
+		}
+	}
+
+	deps := make([]Dependency, 0, len(slots))
+	for name, names := range slots {
+		dep := Dependency{From: prog.Name, To: name, Kind: DepComponent}
+		if len(names) > 0 {
+			dep.Slots = slices.Sorted(maps.Keys(names))
+		}
+
+		deps = append(deps, dep)
+	}
+
+	return deps
+}
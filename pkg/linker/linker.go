@@ -2,6 +2,7 @@ package linker
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/textwire/textwire/v4/pkg/ast"
 	"github.com/textwire/textwire/v4/pkg/fail"
@@ -16,6 +17,9 @@ type NodeLinker struct {
 	mu        sync.RWMutex
 }
 
+// generation is incremented every time programs are linked.
+var generation atomic.Uint64
+
 func New(progs []*ast.Program) *NodeLinker {
 	if progs == nil {
 		progs = make([]*ast.Program, 0, 4)
@@ -28,6 +32,7 @@ func New(progs []*ast.Program) *NodeLinker {
 // CompProg is the parsed program AST of the `book.tw` component.
 func (nl *NodeLinker) LinkNodes() *fail.Error {
 	nl.unlinkAll()
+	nl.invalidateCaches()
 
 	for _, prog := range nl.Programs {
 		if err := nl.handleLayoutLinking(prog); err != nil {
@@ -44,6 +49,18 @@ func (nl *NodeLinker) LinkNodes() *fail.Error {
 	return nil
 }
 
+// invalidateCaches sets a new generation to all @cache directives, so that
+// content cached before templates were changed is never used again.
+func (nl *NodeLinker) invalidateCaches() {
+	gen := generation.Add(1)
+
+	for _, prog := range nl.Programs {
+		for _, cache := range prog.Caches {
+			cache.Generation = gen
+		}
+	}
+}
+
 // unlinkAll unlinks everything from AST nodes to ensure clean state.
 func (nl *NodeLinker) unlinkAll() {
 	for _, prog := range nl.Programs {
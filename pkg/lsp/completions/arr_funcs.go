@@ -0,0 +1,241 @@
+package completions
+
+import (
+	"fmt"
+
+	"github.com/textwire/textwire/v4/pkg/lsp"
+	"github.com/textwire/textwire/v4/pkg/lsp/utils"
+)
+
+func GetArrFuncs(locale lsp.Locale) ([]Completion, error) {
+	completions := map[lsp.Locale][]Completion{
+		"en": {
+			{
+				Label:            "len",
+				InsertText:       "len()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) len(): int",
+					"Returns the number of elements in the array."),
+			},
+			{
+				Label:            "join",
+				InsertText:       "join('${1:, }')",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) join(sep?: str): str",
+					"Joins the elements into a string with the given separator. Defaults to "+
+						"`,`."),
+			},
+			{
+				Label:            "rand",
+				InsertText:       "rand()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) rand(): any",
+					"Returns a random element of the array."),
+			},
+			{
+				Label:            "reverse",
+				InsertText:       "reverse()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) reverse(): arr",
+					"Reverses the order of the elements."),
+			},
+			{
+				Label:            "slice",
+				InsertText:       "slice(${1:0})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) slice(start: int, end?: int): arr",
+					"Returns the elements from the start index up to the end index."),
+			},
+			{
+				Label:            "shuffle",
+				InsertText:       "shuffle()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) shuffle(): arr",
+					"Returns the elements in random order."),
+			},
+			{
+				Label:            "contains",
+				InsertText:       "contains(${1:elem})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) contains(elem: any): bool",
+					"Returns `true` if the array contains the given element."),
+			},
+			{
+				Label:            "append",
+				InsertText:       "append(${1:elem})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) append(...elems: any): arr",
+					"Adds the given elements to the end of the array."),
+			},
+			{
+				Label:            "prepend",
+				InsertText:       "prepend(${1:elem})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) prepend(...elems: any): arr",
+					"Adds the given elements to the start of the array."),
+			},
+			{
+				Label:            "sort",
+				InsertText:       "sort()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) sort(): arr",
+					"Sorts numbers, strings and times in ascending order."),
+			},
+			{
+				Label:            "sortBy",
+				InsertText:       "sortBy('${1:key}')",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) sortBy(key: str | lambda): arr",
+					"Sorts objects by the given key. Nested keys are separated with dots, "+
+						"like `user.name`."),
+			},
+			{
+				Label:            "unique",
+				InsertText:       "unique()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) unique(): arr",
+					"Removes duplicate elements."),
+			},
+			{
+				Label:            "filter",
+				InsertText:       "filter(${1:item} => ${2:item})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) filter(key?: str | lambda, value?: any): arr",
+					"Keeps truthy elements. With a key or a lambda keeps elements where "+
+						"the key or the lambda result is truthy, with a key and a value keeps "+
+						"objects where the key equals the value."),
+			},
+			{
+				Label:            "map",
+				InsertText:       "map(${1:item} => ${2:item})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) map(key: str | lambda): arr",
+					"Returns the result of the lambda for every element, like "+
+						"`users.map(u => u.name)`, or the value of the given key from "+
+						"every object."),
+			},
+			{
+				Label:            "groupBy",
+				InsertText:       "groupBy('${1:key}')",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) groupBy(key: str | lambda): obj",
+					"Groups objects into an object of arrays by the value of the given key."),
+			},
+			{
+				Label:            "chunk",
+				InsertText:       "chunk(${1:2})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) chunk(size: int): arr",
+					"Splits the array into arrays of the given size."),
+			},
+			{
+				Label:            "first",
+				InsertText:       "first()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) first(): any",
+					"Returns the first element or `nil` for an empty array."),
+			},
+			{
+				Label:            "last",
+				InsertText:       "last()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) last(): any",
+					"Returns the last element or `nil` for an empty array."),
+			},
+			{
+				Label:            "sum",
+				InsertText:       "sum()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) sum(key?: str | lambda): int | float",
+					"Returns the sum of the numbers. With a key sums the values of the key "+
+						"in every object."),
+			},
+			{
+				Label:            "avg",
+				InsertText:       "avg()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) avg(key?: str | lambda): float",
+					"Returns the average of the numbers or `nil` for an empty array. With a "+
+						"key uses the values of the key in every object."),
+			},
+			{
+				Label:            "min",
+				InsertText:       "min()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) min(key?: str | lambda): int | float",
+					"Returns the smallest number or `nil` for an empty array. With a key "+
+						"uses the values of the key in every object."),
+			},
+			{
+				Label:            "max",
+				InsertText:       "max()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) max(key?: str | lambda): int | float",
+					"Returns the biggest number or `nil` for an empty array. With a key "+
+						"uses the values of the key in every object."),
+			},
+			{
+				Label:            "pluck",
+				InsertText:       "pluck('${1:key}')",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) pluck(key: str | lambda): arr",
+					"Returns the value of the given key from every object."),
+			},
+			{
+				Label:            "indexOf",
+				InsertText:       "indexOf(${1:elem})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) indexOf(elem: any): int",
+					"Returns the index of the given element or `-1` if it's not found."),
+			},
+			{
+				Label:            "flatten",
+				InsertText:       "flatten()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) flatten(depth?: int): arr",
+					"Flattens nested arrays. The optional depth limits how many levels are "+
+						"flattened."),
+			},
+			{
+				Label:            "json",
+				InsertText:       "json()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) json(): str",
+					"Converts the array to a JSON string."),
+			},
+		},
+	}
+
+	_, ok := completions[locale]
+	if !ok {
+		return []Completion{}, utils.ErrInvalidLocale(string(locale))
+	}
+
+	return completions[locale], nil
+}
@@ -0,0 +1,28 @@
+package completions
+
+import "testing"
+
+func TestGetArrFuncs(t *testing.T) {
+	if _, err := GetArrFuncs("invalid"); err == nil {
+		t.Fatal("expect error for invalid locale, got nil")
+	}
+
+	funcs, err := GetArrFuncs("en")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	labels := map[string]bool{}
+	for _, fn := range funcs {
+		if fn.Documentation == "" {
+			t.Fatalf("expect documentation for %s() function", fn.Label)
+		}
+		labels[fn.Label] = true
+	}
+
+	for _, name := range []string{"sort", "sortBy", "filter", "groupBy", "flatten"} {
+		if !labels[name] {
+			t.Fatalf("GetArrFuncs() should return %s() function", name)
+		}
+	}
+}
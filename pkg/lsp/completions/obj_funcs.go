@@ -0,0 +1,114 @@
+package completions
+
+import (
+	"fmt"
+
+	"github.com/textwire/textwire/v4/pkg/lsp"
+	"github.com/textwire/textwire/v4/pkg/lsp/utils"
+)
+
+func GetObjFuncs(locale lsp.Locale) ([]Completion, error) {
+	completions := map[lsp.Locale][]Completion{
+		"en": {
+			{
+				Label:            "json",
+				InsertText:       "json()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) json(): str",
+					"Converts the object to a JSON string."),
+			},
+			{
+				Label:            "camel",
+				InsertText:       "camel()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) camel(): obj",
+					"Converts the keys of the object to camel case recursively."),
+			},
+			{
+				Label:            "get",
+				InsertText:       "get('${1:key}')",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) get(key: str): any",
+					"Returns the value of the given key. Nested keys are separated with "+
+						"dots, like `user.name`."),
+			},
+			{
+				Label:            "keys",
+				InsertText:       "keys()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) keys(): arr",
+					"Returns the sorted keys of the object."),
+			},
+			{
+				Label:            "values",
+				InsertText:       "values()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) values(): arr",
+					"Returns the values of the object sorted by their keys."),
+			},
+			{
+				Label:            "entries",
+				InsertText:       "entries()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) entries(): arr",
+					"Returns the pairs of the object as objects with the `key` and `value` "+
+						"fields, sorted by their keys."),
+			},
+			{
+				Label:            "len",
+				InsertText:       "len()",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) len(): int",
+					"Returns the number of keys in the object."),
+			},
+			{
+				Label:            "has",
+				InsertText:       "has('${1:key}')",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) has(key: str): bool",
+					"Returns `true` if the object has the given key, even when its value is "+
+						"`nil`. Nested keys are separated with dots."),
+			},
+			{
+				Label:            "merge",
+				InsertText:       "merge(${1:other})",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) merge(...others: obj): obj",
+					"Returns a new object with the keys of all the objects. Keys of the "+
+						"later objects override earlier ones."),
+			},
+			{
+				Label:            "pick",
+				InsertText:       "pick('${1:key}')",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) pick(...keys: str): obj",
+					"Returns a new object with only the given keys."),
+			},
+			{
+				Label:            "omit",
+				InsertText:       "omit('${1:key}')",
+				InsertTextFormat: 2, // 2 = Snippet
+				Documentation: fmt.Sprintf("%s\n%s",
+					"(method) omit(...keys: str): obj",
+					"Returns a new object without the given keys."),
+			},
+		},
+	}
+
+	_, ok := completions[locale]
+	if !ok {
+		return []Completion{}, utils.ErrInvalidLocale(string(locale))
+	}
+
+	return completions[locale], nil
+}
@@ -0,0 +1,25 @@
+package completions
+
+import "testing"
+
+func TestGetObjFuncs(t *testing.T) {
+	if _, err := GetObjFuncs("invalid"); err == nil {
+		t.Fatal("expect error for invalid locale, got nil")
+	}
+
+	funcs, err := GetObjFuncs("en")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	labels := map[string]bool{}
+	for _, fn := range funcs {
+		labels[fn.Label] = true
+	}
+
+	for _, name := range []string{"keys", "values", "has", "merge", "pick", "omit", "entries"} {
+		if !labels[name] {
+			t.Fatalf("GetObjFuncs() should return %s() function", name)
+		}
+	}
+}
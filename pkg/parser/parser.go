@@ -2,6 +2,7 @@ package parser
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/textwire/textwire/v4/pkg/file"
 	"github.com/textwire/textwire/v4/pkg/position"
@@ -27,6 +28,7 @@ const (
 	LOGICAL_AND   // &&
 	EQ            // ==
 	LESS_GREATER  // > or <
+	CONCAT        // ~
 	SUM           // +
 	PRODUCT       // *
 	PREFIX        // -X or !X
@@ -48,6 +50,7 @@ var precedences = map[token.TokenType]int{
 	token.GTHAN_EQ: LESS_GREATER,
 	token.ADD:      SUM,
 	token.SUB:      SUM,
+	token.CONCAT:   CONCAT,
 	token.DIV:      PRODUCT,
 	token.MOD:      PRODUCT,
 	token.MUL:      PRODUCT,
@@ -69,6 +72,11 @@ type Parser struct {
 	infixParseFns  map[token.TokenType]infixParseFn
 
 	prog *ast.Program
+
+	// cacheLoops counts loops opened inside the innermost @cache
+	// directive and is -1 outside of @cache. Loop directives like @break
+	// can't stop loops around @cache, cache hits don't evaluate them.
+	cacheLoops int
 }
 
 func New(lexer *lexer.Lexer, f *file.SourceFile) *Parser {
@@ -77,9 +85,10 @@ func New(lexer *lexer.Lexer, f *file.SourceFile) *Parser {
 	}
 
 	p := &Parser{
-		l:      lexer,
-		file:   f,
-		errors: []*fail.Error{},
+		l:          lexer,
+		file:       f,
+		errors:     []*fail.Error{},
+		cacheLoops: -1,
 	}
 
 	p.nextToken() // fill curToken
@@ -108,6 +117,7 @@ func New(lexer *lexer.Lexer, f *file.SourceFile) *Parser {
 	p.registerInfix(token.MUL, p.infixExpr)
 	p.registerInfix(token.DIV, p.infixExpr)
 	p.registerInfix(token.MOD, p.infixExpr)
+	p.registerInfix(token.CONCAT, p.infixExpr)
 
 	p.registerInfix(token.EQ, p.infixExpr)
 	p.registerInfix(token.NOT_EQ, p.infixExpr)
@@ -157,6 +167,8 @@ func (p *Parser) chunk() ast.Chunk {
 		return p.embedded()
 	case token.IF:
 		return p.ifDir()
+	case token.SWITCH:
+		return p.switchDir()
 	case token.FOR:
 		return p.forDir()
 	case token.EACH:
@@ -168,8 +180,10 @@ func (p *Parser) chunk() ast.Chunk {
 	case token.INSERT:
 		return p.insertDir()
 	case token.BREAKIF:
+		p.checkLoopDirInCache()
 		return p.breakifDir()
 	case token.CONTINUEIF:
+		p.checkLoopDirInCache()
 		return p.continueifDir()
 	case token.COMPONENT:
 		return p.compDir()
@@ -179,9 +193,13 @@ func (p *Parser) chunk() ast.Chunk {
 		return p.passDir()
 	case token.DUMP:
 		return p.dumpDir()
+	case token.CACHE:
+		return p.cacheDir()
 	case token.BREAK:
+		p.checkLoopDirInCache()
 		return ast.NewBreakDir(p.curToken)
 	case token.CONTINUE:
+		p.checkLoopDirInCache()
 		return ast.NewContinueDir(p.curToken)
 	}
 
@@ -332,6 +350,10 @@ func (p *Parser) ident() ast.Expression {
 		return p.globalCallExpr(ident)
 	}
 
+	if p.peekTokenIs(token.ARROW) {
+		return p.lambdaExpr(p.curToken, []*ast.IdentExpr{ident})
+	}
+
 	return ident
 }
 
@@ -648,6 +670,41 @@ func (p *Parser) dumpDir() ast.Chunk {
 	return dir
 }
 
+func (p *Parser) cacheDir() ast.Chunk {
+	cacheDir := ast.NewCacheDir(p.curToken)
+
+	if !p.expectPeek(token.LPAREN) { // move to "("
+		return p.illegal()
+	}
+
+	args := p.expressionList(token.RPAREN) // moves to ")"
+	if len(args) == 0 || len(args) > 2 {
+		p.newError(cacheDir.Pos(), fail.ErrCacheDirArgs, len(args))
+		return p.illegalUntil(token.END)
+	}
+
+	cacheDir.Key = args[0]
+	if len(args) == 2 {
+		cacheDir.TTL = args[1]
+	}
+
+	p.nextToken() // skip ")"
+
+	defer func(loops int) { p.cacheLoops = loops }(p.cacheLoops)
+	p.cacheLoops = 0
+
+	cacheDir.Block = p.block()
+
+	if !p.curTokenIs(token.END) {
+		return p.illegalUntil(token.END)
+	}
+
+	cacheDir.SetEndPosition(p.curToken.Pos)
+	p.prog.Caches = append(p.prog.Caches, cacheDir)
+
+	return cacheDir
+}
+
 func (p *Parser) passDir() ast.Chunk {
 	passDir := ast.NewPassDir(p.curToken, nil)
 	hasCondition := p.curToken.Type == token.PASSIF
@@ -1063,6 +1120,79 @@ func (p *Parser) elseBlock() *ast.Block {
 	return block
 }
 
+func (p *Parser) switchDir() ast.Chunk {
+	dir := ast.NewSwitchDir(p.curToken)
+
+	if !p.expectPeek(token.LPAREN) { // move to "("
+		return p.illegal()
+	}
+
+	p.nextToken() // skip "("
+
+	dir.Value = p.expression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) { // move to ")"
+		return p.illegal()
+	}
+
+	p.nextToken() // skip ")"
+
+	// Whitespace before the first case is not part of any case. Comments
+	// split it into multiple text tokens
+	for p.curTokenIs(token.TEXT) && strings.TrimSpace(p.curToken.Lit) == "" {
+		p.nextToken() // skip whitespace
+	}
+
+	for p.curTokenIs(token.CASE) {
+		caseDir, illegal := p.caseDir()
+		if illegal != nil {
+			return illegal
+		}
+
+		dir.Cases = append(dir.Cases, caseDir)
+	}
+
+	if p.curTokenIs(token.DEFAULT) {
+		p.nextToken() // skip "@default"
+		dir.DefaultBlock = p.block()
+
+		if p.curTokenIs(token.CASE) {
+			p.newError(p.curToken.Pos, fail.ErrCaseAfterDefault)
+			return p.illegalUntil(token.END)
+		}
+	}
+
+	if !p.curTokenIs(token.END) {
+		p.newError(p.curToken.Pos, fail.ErrSwitchExpectsCase, p.curToken.Lit)
+		return p.illegalUntil(token.END)
+	}
+
+	dir.SetEndPosition(p.curToken.Pos)
+
+	return dir
+}
+
+func (p *Parser) caseDir() (*ast.CaseDir, *ast.Illegal) {
+	dir := ast.NewCaseDir(p.curToken)
+
+	if !p.expectPeek(token.LPAREN) { // move to "("
+		return nil, p.illegal()
+	}
+
+	dir.Values = p.expressionList(token.RPAREN) // moves to ")"
+	if len(dir.Values) == 0 {
+		p.newError(dir.Pos(), fail.ErrCaseDirArgs)
+		return nil, p.illegalUntil(token.END)
+	}
+
+	p.nextToken() // skip ")"
+
+	dir.Block = p.block()
+	dir.SetEndPosition(p.curToken.Pos)
+
+	return dir, nil
+}
+
 func (p *Parser) forDir() ast.Chunk {
 	dir := ast.NewForDir(p.curToken)
 
@@ -1070,7 +1200,7 @@ func (p *Parser) forDir() ast.Chunk {
 		return illegal
 	}
 
-	dir.Block = p.block()
+	dir.Block = p.loopBlock()
 
 	if p.curTokenIs(token.END) {
 		dir.SetEndPosition(p.curToken.Pos)
@@ -1167,7 +1297,7 @@ func (p *Parser) eachDir() ast.Chunk {
 		return illegal
 	}
 
-	dir.Block = p.block()
+	dir.Block = p.loopBlock()
 
 	if p.curTokenIs(token.END) {
 		dir.SetEndPosition(p.curToken.Pos)
@@ -1215,8 +1345,26 @@ func (p *Parser) eachDirHeader(dir *ast.EachDir) *ast.Illegal {
 	return nil
 }
 
+// loopBlock parses the body of @for or @each directive.
+func (p *Parser) loopBlock() *ast.Block {
+	if p.cacheLoops >= 0 {
+		p.cacheLoops++
+		defer func() { p.cacheLoops-- }()
+	}
+
+	return p.block()
+}
+
+// checkLoopDirInCache reports an error when the current loop directive,
+// like @break, would stop a loop outside of the @cache it's in.
+func (p *Parser) checkLoopDirInCache() {
+	if p.cacheLoops == 0 {
+		p.newError(p.curToken.Pos, fail.ErrLoopDirInCache, p.curToken.Lit)
+	}
+}
+
 func (p *Parser) block() *ast.Block {
-	if p.curTokenIs(token.ELSE, token.ELSEIF, token.END) {
+	if p.curTokenIs(token.ELSE, token.ELSEIF, token.CASE, token.DEFAULT, token.END) {
 		return nil
 	}
 
@@ -1231,7 +1379,7 @@ func (p *Parser) block() *ast.Block {
 			block.Chunks = append(block.Chunks, chunk)
 		}
 
-		if p.peekTokenIs(token.ELSE, token.ELSEIF, token.END) {
+		if p.peekTokenIs(token.ELSE, token.ELSEIF, token.CASE, token.DEFAULT, token.END) {
 			p.nextToken() // skip chunk
 			break
 		}
@@ -1239,7 +1387,11 @@ func (p *Parser) block() *ast.Block {
 		p.nextToken() // skip chunk
 	}
 
-	block.Trim()
+	// Directive lines are already removed by the lexer, whitespace that
+	// is left inside the block is intentional
+	if !p.l.TrimsDirectiveLines() {
+		block.Trim()
+	}
 
 	return block
 }
@@ -1299,18 +1451,87 @@ func (p *Parser) prefixExpr() ast.Expression {
 func (p *Parser) groupedExpr() ast.Expression {
 	exprTok := p.curToken
 
+	// lambda without parameters, like "() => 1"
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken() // move to ")"
+		if !p.peekTokenIs(token.ARROW) {
+			p.nextToken()
+			return p.illegal()
+		}
+		return p.lambdaExpr(exprTok, []*ast.IdentExpr{})
+	}
+
 	p.nextToken() // skip "("
 
 	expr := p.expression(LOWEST)
+
+	// lambda with many parameters, like "(a, b) => a + b"
+	if ident, ok := expr.(*ast.IdentExpr); ok && p.peekTokenIs(token.COMMA) {
+		return p.lambdaParams(exprTok, ident)
+	}
+
 	expr.SetTok(exprTok)
 
 	if !p.expectPeek(token.RPAREN) { // move to ")"
 		return p.illegal()
 	}
 
+	// lambda with a parameter in parentheses, like "(u) => u.name"
+	if ident, ok := expr.(*ast.IdentExpr); ok && p.peekTokenIs(token.ARROW) {
+		return p.lambdaExpr(exprTok, []*ast.IdentExpr{ident})
+	}
+
 	return expr
 }
 
+// lambdaParams parses the rest of the lambda parameters, like ", b) =>"
+func (p *Parser) lambdaParams(tok token.Token, first *ast.IdentExpr) ast.Expression {
+	params := []*ast.IdentExpr{first}
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken() // move to ","
+		if !p.expectPeek(token.IDENT) {
+			return p.illegal()
+		}
+		params = append(params, ast.NewIdentExpr(p.curToken, p.curToken.Lit))
+	}
+
+	if !p.expectPeek(token.RPAREN) { // move to ")"
+		return p.illegal()
+	}
+
+	if !p.peekTokenIs(token.ARROW) {
+		p.nextToken()
+		return p.illegal()
+	}
+
+	return p.lambdaExpr(tok, params)
+}
+
+// lambdaExpr parses the body of the lambda, like "=> u.name". The current
+// token is the last token before "=>".
+func (p *Parser) lambdaExpr(tok token.Token, params []*ast.IdentExpr) ast.Expression {
+	// Copy the position to not extend the position of the first parameter
+	pos := *tok.Pos
+	tok.Pos = &pos
+
+	lambda := ast.NewLambdaExpr(tok, params)
+
+	p.nextToken() // move to "=>"
+
+	if p.peekTokenIs(token.RPAREN, token.RBRACES, token.COMMA, token.EOF) {
+		p.newError(p.curToken.Pos, fail.ErrExpectExprAfter, token.String(token.ARROW))
+		return ast.NewIllegalNode(p.curToken)
+	}
+
+	p.nextToken() // skip "=>"
+
+	lambda.Body = p.expression(LOWEST)
+	lambda.SetEndPosition(p.curToken.Pos)
+
+	return lambda
+}
+
 func (p *Parser) expressionList(endTok token.TokenType) []ast.Expression {
 	firstTok := p.curToken
 	var exprs []ast.Expression
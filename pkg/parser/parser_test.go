@@ -259,6 +259,7 @@ func TestParseInfixExp(t *testing.T) {
 		{"{{ 2 * 2 }}", 2, "*", 2, 7, token.INT},
 		{"{{ 44 / 4 }}", 44, "/", 4, 8, token.INT},
 		{"{{ 5 % 4 }}", 5, "%", 4, 7, token.INT},
+		{"{{ 5 ~ 4 }}", 5, "~", 4, 7, token.INT},
 		{`{{ "me" + "her" }}`, "me", "+", "her", 14, token.STR},
 		{`{{ 14 == 14 }}`, 14, "==", 14, 10, token.INT},
 		{`{{ 10 != 1 }}`, 10, "!=", 1, 9, token.INT},
@@ -466,6 +467,36 @@ func TestParseOperatorPrecedence(t *testing.T) {
 			inp:    "{{ user && user.name == 'serhii' }}",
 			expect: `{{ (user && ((user.name) == "serhii")) }}`,
 		},
+		{
+			id:     170,
+			inp:    "{{ users.filter(u => u.active) }}",
+			expect: "{{ (users.filter(((u) => (u.active)))) }}",
+		},
+		{
+			id:     180,
+			inp:    "{{ users.map((u) => u.age + 1) }}",
+			expect: "{{ (users.map(((u) => ((u.age) + 1)))) }}",
+		},
+		{
+			id:     190,
+			inp:    "{{ items.map((item, i) => i ? item : 'first') }}",
+			expect: `{{ (items.map(((item, i) => (i ? item : "first")))) }}`,
+		},
+		{
+			id:     200,
+			inp:    "{{ fn = () => 1; (a) + 1 }}",
+			expect: "{{ (fn = (() => 1)); (a + 1) }}",
+		},
+		{
+			id:     210,
+			inp:    "{{ 'Total: ' ~ a + b * 2 }}",
+			expect: `{{ ("Total: " ~ (a + (b * 2))) }}`,
+		},
+		{
+			id:     220,
+			inp:    "{{ a ~ b == 'ab' }}",
+			expect: `{{ ((a ~ b) == "ab") }}`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -555,13 +586,13 @@ func TestErrorHandling(t *testing.T) {
 		},
 		{
 			id:  50,
-			inp: `{{ 1 ~ 8 }}`,
+			inp: `{{ 1 ^ 8 }}`,
 			err: fail.New(
 				&position.Pos{StartCol: 5, EndCol: 5},
 				"",
 				fail.OriginPars,
 				fail.ErrIllegalToken,
-				"~",
+				"^",
 			),
 		},
 		{
@@ -935,15 +966,15 @@ func TestErrorHandling(t *testing.T) {
 		},
 		{
 			id:  940,
-			inp: "{{ formatDate(str, str2, str3) }}",
+			inp: "{{ formatDate(str, str2, str3, str4) }}",
 			err: fail.New(
-				&position.Pos{StartCol: 3, EndCol: 29},
+				&position.Pos{StartCol: 3, EndCol: 35},
 				"",
 				fail.OriginPars,
 				fail.ErrGlobalFuncLotsOfArgs,
 				"formatDate",
-				2,
 				3,
+				4,
 			),
 		},
 		{
@@ -969,6 +1000,104 @@ func TestErrorHandling(t *testing.T) {
 				"",
 			),
 		},
+		{
+			id:  970,
+			inp: "@cache()<b></b>@end",
+			err: fail.New(
+				&position.Pos{StartCol: 0, EndCol: 5},
+				"",
+				fail.OriginPars,
+				fail.ErrCacheDirArgs,
+				0,
+			),
+		},
+		{
+			id:  980,
+			inp: "@cache('key', 60, 1)<b></b>@end",
+			err: fail.New(
+				&position.Pos{StartCol: 0, EndCol: 5},
+				"",
+				fail.OriginPars,
+				fail.ErrCacheDirArgs,
+				3,
+			),
+		},
+		{
+			id:  990,
+			inp: "{{ users.map(u =>) }}",
+			err: fail.New(
+				&position.Pos{StartCol: 15, EndCol: 16},
+				"",
+				fail.OriginPars,
+				fail.ErrExpectExprAfter,
+				token.String(token.ARROW),
+			),
+		},
+		{
+			id:  1000,
+			inp: "{{ users.map((u, 1) => u) }}",
+			err: fail.New(
+				&position.Pos{StartCol: 17, EndCol: 17},
+				"",
+				fail.OriginPars,
+				fail.ErrWrongPeekToken,
+				token.String(token.IDENT),
+				"1",
+			),
+		},
+		{
+			id:  1010,
+			inp: "@switch(x)@case()A@end",
+			err: fail.New(
+				&position.Pos{StartCol: 10, EndCol: 14},
+				"",
+				fail.OriginPars,
+				fail.ErrCaseDirArgs,
+			),
+		},
+		{
+			id:  1020,
+			inp: "@switch(x)@defaultA@case(1)B@end",
+			err: fail.New(
+				&position.Pos{StartCol: 19, EndCol: 23},
+				"",
+				fail.OriginPars,
+				fail.ErrCaseAfterDefault,
+			),
+		},
+		{
+			id:  1030,
+			inp: "@switch(x)text@case(1)B@end",
+			err: fail.New(
+				&position.Pos{StartCol: 10, EndCol: 13},
+				"",
+				fail.OriginPars,
+				fail.ErrSwitchExpectsCase,
+				"text",
+			),
+		},
+		{
+			id:  1040,
+			inp: "@each(x in xs)@cache('a')@break@end@end",
+			err: fail.New(
+				&position.Pos{StartCol: 25, EndCol: 30},
+				"",
+				fail.OriginPars,
+				fail.ErrLoopDirInCache,
+				"@break",
+			),
+		},
+		{
+			id:  1050,
+			inp: "@for(i = 0; i < 3; i++)@cache(i)@if(i)@continueif(true)@end@end@end",
+			err: fail.New(
+				&position.Pos{StartCol: 38, EndCol: 54},
+				"",
+				fail.OriginPars,
+				fail.ErrLoopDirInCache,
+				"@continueif",
+			),
+		},
 	}
 
 	for _, tc := range cases {
@@ -996,6 +1125,18 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
+func TestParseLoopDirInsideCachedLoop(t *testing.T) {
+	inp := "@cache('a')@each(x in xs)@break@else@end@for(;;)@continue@end@end"
+
+	l := lexer.New(inp)
+	p := New(l, nil)
+	p.ParseProgram()
+
+	if p.HasErrors() {
+		t.Fatalf("unexpected error: %s", p.Errors()[0])
+	}
+}
+
 func TestParseTernaryExpr(t *testing.T) {
 	inp := `{{ true ? 100 : "Some string" }}`
 
@@ -1187,6 +1328,77 @@ func TestParseIfElseIfDir(t *testing.T) {
 	}
 }
 
+func TestParseSwitchDir(t *testing.T) {
+	inp := `@switch(status)@case('paid', 'shipped')first@case('refunded')second@defaultthird@end`
+
+	switchDir, err := parseDirective[*ast.SwitchDir](inp, defaultParseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := testIdentExpr(switchDir.Value, "status"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(switchDir.Cases) != 2 {
+		t.Fatalf("switchDir.Cases does not contain 2 cases, got %d", len(switchDir.Cases))
+	}
+
+	cases := []struct {
+		values []string
+		text   string
+	}{
+		{[]string{"paid", "shipped"}, "first"},
+		{[]string{"refunded"}, "second"},
+	}
+
+	for i, tc := range cases {
+		caseDir := switchDir.Cases[i]
+
+		if len(caseDir.Values) != len(tc.values) {
+			t.Fatalf("Case %d must have %d values, got %d", i, len(tc.values), len(caseDir.Values))
+		}
+
+		for j, val := range tc.values {
+			if err := testStrExpr(caseDir.Values[j], val); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if caseDir.Block.String() != tc.text {
+			t.Fatalf("caseDir.Block.String() is not %q, got %q", tc.text, caseDir.Block)
+		}
+	}
+
+	if switchDir.DefaultBlock.String() != "third" {
+		t.Fatalf("switchDir.DefaultBlock is not %q, got %q", "third", switchDir.DefaultBlock)
+	}
+
+	expect := "@switch(status)\n@case(\"paid\", \"shipped\")\nfirst@case(\"refunded\")\n" +
+		"second@default\nthird\n@end\n"
+	if switchDir.String() != expect {
+		t.Fatalf("switchDir.String() is not %q, got %q", expect, switchDir)
+	}
+}
+
+func TestParseSwitchDirWithComments(t *testing.T) {
+	inp := "@switch(x)\n  {{-- note --}}\n  {{-- other --}}\n  @case(5)five\n" +
+		"  {{-- between --}}\n  @case(6)six@end"
+
+	switchDir, err := parseDirective[*ast.SwitchDir](inp, defaultParseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(switchDir.Cases) != 2 {
+		t.Fatalf("switchDir.Cases does not contain 2 cases, got %d", len(switchDir.Cases))
+	}
+
+	if text := switchDir.Cases[1].Block.String(); text != "six" {
+		t.Fatalf("second case block is not %q, got %q", "six", text)
+	}
+}
+
 func TestParseElseIfWithElseDir(t *testing.T) {
 	inp := `@if(true)1@elseif(false)2@else3@end`
 
@@ -2480,6 +2692,44 @@ func TestParseSlotDir(t *testing.T) {
 	})
 }
 
+func TestParseCacheDir(t *testing.T) {
+	inp := `@cache('sidebar', 60)<aside>{{ id }}</aside>@end`
+
+	cacheDir, err := parseDirective[*ast.CacheDir](inp, defaultParseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := testToken(cacheDir, token.CACHE); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := testStrExpr(cacheDir.Key, "sidebar"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := testIntExpr(cacheDir.TTL, 60); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cacheDir.Block.Chunks) != 3 {
+		t.Fatalf("len(cacheDir.Block.Chunks) is not 3, got %d", len(cacheDir.Block.Chunks))
+	}
+
+	err = testTokPosition(cacheDir.Pos(), &position.Pos{
+		StartCol: 0,
+		EndCol:   uint(len(inp) - 1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `@cache("sidebar", 60)<aside>{{ id }}</aside>@end`
+	if cacheDir.String() != expect {
+		t.Fatalf("cacheDir.String() is not %s, got %s", expect, cacheDir)
+	}
+}
+
 func TestParseDumpDir(t *testing.T) {
 	inp := `@dump("test", 1 + 2, false)`
 
@@ -27,7 +27,7 @@ func parseChunks(inp string, opts parseOpts) ([]ast.Chunk, error) {
 	prog := p.ParseProgram()
 
 	if opts.checkErrors && p.HasErrors() {
-		return nil, p.Errors()[0].Error()
+		return nil, p.Errors()[0]
 	}
 
 	if len(prog.Chunks) != opts.chunksCount {
@@ -0,0 +1,139 @@
+package profile
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+)
+
+// WritePprof writes the profile in the gzipped protobuf format of pprof,
+// so it can be explored with `go tool pprof`. Each sample has the number
+// of calls and the self time in nanoseconds. Functions are the profiled
+// nodes and their file names are template paths.
+func (p *Profile) WritePprof(w io.Writer) error {
+	b := &protoBuf{strings: map[string]int64{"": 0}, table: []string{""}}
+
+	b.message(1, func(b *protoBuf) { // sample_type
+		b.int(1, b.str("calls"))
+		b.int(2, b.str("count"))
+	})
+	b.message(1, func(b *protoBuf) {
+		b.int(1, b.str("time"))
+		b.int(2, b.str("nanoseconds"))
+	})
+
+	for _, sample := range p.Samples {
+		b.message(2, func(b *protoBuf) { // sample
+			ids := make([]uint64, len(sample.Stack))
+			for i, idx := range sample.Stack {
+				ids[i] = uint64(idx + 1)
+			}
+
+			b.packed(1, ids)
+			b.packed(2, []uint64{uint64(sample.Calls), uint64(sample.Self.Nanoseconds())})
+		})
+	}
+
+	for i, entry := range p.Entries {
+		id := int64(i + 1)
+		var line int64
+		if entry.Pos != nil {
+			line = int64(entry.Pos.Line())
+		}
+
+		b.message(4, func(b *protoBuf) { // location
+			b.int(1, id)
+			b.message(4, func(b *protoBuf) {
+				b.int(1, id)
+				b.int(2, line)
+			})
+		})
+
+		b.message(5, func(b *protoBuf) { // function
+			b.int(1, id)
+			b.int(2, b.str(entry.Name))
+			b.int(3, b.str(entry.Name))
+			b.int(4, b.str(entry.Path))
+			b.int(5, line)
+		})
+	}
+
+	// Strings are collected while encoding other fields, so the
+	// string table is written after them.
+	body := b.out
+	b.out = nil
+	for _, s := range b.table {
+		b.bytes(6, []byte(s))
+	}
+
+	b.int(9, p.Start.UnixNano())
+	b.int(10, p.Duration.Nanoseconds())
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(body); err != nil {
+		return err
+	}
+
+	if _, err := zw.Write(b.out); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// protoBuf is a minimal protocol buffers encoder for the pprof format.
+type protoBuf struct {
+	out     []byte
+	strings map[string]int64
+	table   []string
+}
+
+// str returns the index of s in the string table.
+func (b *protoBuf) str(s string) int64 {
+	if idx, ok := b.strings[s]; ok {
+		return idx
+	}
+
+	idx := int64(len(b.table))
+	b.strings[s] = idx
+	b.table = append(b.table, s)
+
+	return idx
+}
+
+func (b *protoBuf) key(field int, wireType byte) {
+	b.out = binary.AppendUvarint(b.out, uint64(field)<<3|uint64(wireType))
+}
+
+func (b *protoBuf) int(field int, v int64) {
+	if v == 0 {
+		return
+	}
+
+	b.key(field, 0)
+	b.out = binary.AppendUvarint(b.out, uint64(v))
+}
+
+func (b *protoBuf) bytes(field int, v []byte) {
+	b.key(field, 2)
+	b.out = binary.AppendUvarint(b.out, uint64(len(v)))
+	b.out = append(b.out, v...)
+}
+
+func (b *protoBuf) packed(field int, vals []uint64) {
+	var data []byte
+	for _, v := range vals {
+		data = binary.AppendUvarint(data, v)
+	}
+
+	b.bytes(field, data)
+}
+
+func (b *protoBuf) message(field int, encode func(b *protoBuf)) {
+	outer := b.out
+	b.out = nil
+	encode(b)
+	inner := b.out
+	b.out = outer
+	b.bytes(field, inner)
+}
@@ -0,0 +1,247 @@
+// Package profile measures how much time evaluation of templates spends in
+// loops, function calls and components, to find what makes templates slow.
+package profile
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/textwire/textwire/v4/pkg/position"
+)
+
+// Kind is the kind of the profiled node.
+type Kind string
+
+const (
+	KindTemplate  Kind = "template"  // The rendered page
+	KindLoop      Kind = "loop"      // @for and @each directives
+	KindCall      Kind = "call"      // Built-in, custom and global function calls
+	KindComponent Kind = "component" // @component directives
+)
+
+// Entry is the evaluation cost of a single node in the template source.
+type Entry struct {
+	Kind Kind
+
+	// Name describes the node, like "@each(book in books)",
+	// "string.upper()" or "components/book".
+	Name string
+
+	// Path is the absolute path to the file with the node.
+	Path string
+
+	Pos *position.Pos
+
+	// Calls is how many times the node was evaluated.
+	Calls int
+
+	// Total is the time spent in the node including nested nodes.
+	// Time of recursive components is only counted once.
+	Total time.Duration
+
+	// Self is the time spent in the node excluding nested profiled nodes.
+	Self time.Duration
+}
+
+// Location returns the file path and line of the node, like "/app/index.tw:4".
+func (e *Entry) Location() string {
+	if e.Pos == nil {
+		return e.Path
+	}
+
+	return e.Path + ":" + strconv.Itoa(int(e.Pos.Line()))
+}
+
+// Sample is the self time of a unique stack of nodes.
+type Sample struct {
+	// Stack holds indexes of Profile.Entries with the innermost node first.
+	Stack []int
+	Calls int
+	Self  time.Duration
+}
+
+// Profile is the result of profiling a single template evaluation.
+type Profile struct {
+	// Template is the name of the profiled template.
+	Template string
+
+	Start    time.Time
+	Duration time.Duration
+
+	// Entries are sorted by total time, the slowest first.
+	Entries []*Entry
+	Samples []*Sample
+}
+
+// Hottest returns up to n entries of the given kind with the highest
+// total time. It returns all of them when n is less than 1.
+func (p *Profile) Hottest(kind Kind, n int) []*Entry {
+	entries := make([]*Entry, 0, max(n, 0))
+	for _, entry := range p.Entries {
+		if entry.Kind != kind {
+			continue
+		}
+
+		entries = append(entries, entry)
+		if len(entries) == n {
+			break
+		}
+	}
+
+	return entries
+}
+
+type entryKey struct {
+	kind Kind
+	name string
+	path string
+	pos  position.Pos
+}
+
+type frame struct {
+	entry    int
+	start    time.Time
+	children time.Duration
+}
+
+// Recorder measures time of nested nodes. It's not safe for concurrent use.
+type Recorder struct {
+	template string
+	start    time.Time
+	entries  []*Entry
+	indexes  map[entryKey]int
+	samples  map[string]*Sample
+	frames   []frame
+}
+
+// NewRecorder returns a recorder for the template with the given name.
+func NewRecorder(template string) *Recorder {
+	return &Recorder{
+		template: template,
+		start:    time.Now(),
+		indexes:  map[entryKey]int{},
+		samples:  map[string]*Sample{},
+	}
+}
+
+// Enter starts measuring the node. Every Enter must be followed by Exit.
+func (r *Recorder) Enter(kind Kind, name, path string, pos *position.Pos) {
+	key := entryKey{kind: kind, name: name, path: path}
+	if pos != nil {
+		key.pos = *pos
+	}
+
+	idx, ok := r.indexes[key]
+	if !ok {
+		idx = len(r.entries)
+		r.indexes[key] = idx
+		r.entries = append(r.entries, &Entry{Kind: kind, Name: name, Path: path, Pos: pos})
+	}
+
+	r.frames = append(r.frames, frame{entry: idx, start: time.Now()})
+}
+
+// Exit stops measuring the node entered last.
+func (r *Recorder) Exit() {
+	last := len(r.frames) - 1
+	f := r.frames[last]
+	r.frames = r.frames[:last]
+
+	elapsed := time.Since(f.start)
+	self := elapsed - f.children
+	entry := r.entries[f.entry]
+
+	entry.Calls++
+	entry.Self += self
+
+	if !r.isActive(f.entry) {
+		entry.Total += elapsed
+	}
+
+	if last > 0 {
+		r.frames[last-1].children += elapsed
+	}
+
+	stack := make([]int, 0, last+1)
+	stack = append(stack, f.entry)
+	for i := last - 1; i >= 0; i-- {
+		stack = append(stack, r.frames[i].entry)
+	}
+
+	key := stackKey(stack)
+	sample, ok := r.samples[key]
+	if !ok {
+		sample = &Sample{Stack: stack}
+		r.samples[key] = sample
+	}
+
+	sample.Calls++
+	sample.Self += self
+}
+
+// isActive reports whether the entry is still on the stack,
+// like a component that renders itself recursively.
+func (r *Recorder) isActive(entry int) bool {
+	for _, f := range r.frames {
+		if f.entry == entry {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Profile returns the recorded profile. Entries keep their indexes
+// in samples, but are sorted by total time.
+func (r *Recorder) Profile() *Profile {
+	order := make([]int, len(r.entries))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(r.entries[b].Total, r.entries[a].Total)
+	})
+
+	newIdx := make([]int, len(order))
+	entries := make([]*Entry, len(order))
+	for i, old := range order {
+		newIdx[old] = i
+		entries[i] = r.entries[old]
+	}
+
+	samples := make([]*Sample, 0, len(r.samples))
+	for _, sample := range r.samples {
+		stack := make([]int, len(sample.Stack))
+		for i, idx := range sample.Stack {
+			stack[i] = newIdx[idx]
+		}
+
+		samples = append(samples, &Sample{Stack: stack, Calls: sample.Calls, Self: sample.Self})
+	}
+
+	slices.SortFunc(samples, func(a, b *Sample) int {
+		return cmp.Compare(b.Self, a.Self)
+	})
+
+	return &Profile{
+		Template: r.template,
+		Start:    r.start,
+		Duration: time.Since(r.start),
+		Entries:  entries,
+		Samples:  samples,
+	}
+}
+
+func stackKey(stack []int) string {
+	var out strings.Builder
+	for _, idx := range stack {
+		out.WriteString(strconv.Itoa(idx))
+		out.WriteByte(',')
+	}
+
+	return out.String()
+}
@@ -0,0 +1,62 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+var textSections = []struct {
+	kind  Kind
+	title string
+}{
+	{KindLoop, "Loops"},
+	{KindCall, "Function calls"},
+	{KindComponent, "Components"},
+}
+
+// WriteText writes up to n hottest loops, function calls and components
+// as text tables. All of them are written when n is less than 1.
+func (p *Profile) WriteText(w io.Writer, n int) error {
+	_, err := fmt.Fprintf(w, "Profile of %q, total %s\n", p.Template, formatDuration(p.Duration))
+	if err != nil {
+		return err
+	}
+
+	for _, section := range textSections {
+		entries := p.Hottest(section.kind, n)
+		if len(entries) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "\n%s:\n", section.title); err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, "TOTAL\tSELF\tCALLS\t  LOCATION")
+
+		for _, entry := range entries {
+			fmt.Fprintf(
+				tw,
+				"%s\t%s\t%d\t  %s %s\n",
+				formatDuration(entry.Total),
+				formatDuration(entry.Self),
+				entry.Calls,
+				entry.Location(),
+				entry.Name,
+			)
+		}
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
@@ -23,16 +23,18 @@ const (
 	NOT // !
 
 	// Operators
-	ADD // +
-	SUB // -
-	MUL // *
-	DIV // /
-	MOD // %
+	ADD    // +
+	SUB    // -
+	MUL    // *
+	DIV    // /
+	MOD    // %
+	CONCAT // ~
 
 	INC // ++
 	DEC // --
 
 	ASSIGN // =
+	ARROW  // =>
 
 	// Comparison operators
 	EQ       // ==
@@ -82,6 +84,10 @@ const (
 	PASSIF
 	PASS
 	DUMP
+	CACHE
+	SWITCH
+	CASE
+	DEFAULT
 )
 
 var keywords = map[string]TokenType{
@@ -111,6 +117,10 @@ var directives = map[string]TokenType{
 	"@dump":       DUMP,
 	"@continueif": CONTINUEIF,
 	"@breakif":    BREAKIF,
+	"@cache":      CACHE,
+	"@switch":     SWITCH,
+	"@case":       CASE,
+	"@default":    DEFAULT,
 }
 
 func GetDirectives() map[string]TokenType {
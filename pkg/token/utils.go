@@ -10,17 +10,19 @@ var tokens = [...]string{
 	FLOAT: "float",
 	STR:   "string",
 
-	ADD: "+",
-	SUB: "-",
-	MUL: "*",
-	DIV: "/",
-	MOD: "%",
+	ADD:    "+",
+	SUB:    "-",
+	MUL:    "*",
+	DIV:    "/",
+	MOD:    "%",
+	CONCAT: "~",
 
 	INC: "++",
 	DEC: "--",
 
 	NOT:    "!",
 	ASSIGN: "=",
+	ARROW:  "=>",
 	EQ:     "==",
 	NOT_EQ: "!=",
 
@@ -65,6 +67,10 @@ var tokens = [...]string{
 	SLOT:       "@slot",
 	PASS:       "@pass",
 	PASSIF:     "@passif",
+	CACHE:      "@cache",
+	SWITCH:     "@switch",
+	CASE:       "@case",
+	DEFAULT:    "@default",
 }
 
 func String(t TokenType) string {
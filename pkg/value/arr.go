@@ -15,13 +15,17 @@ func (*Arr) Type() ValueType {
 }
 
 func (a *Arr) String() string {
+	return a.format(EscapeHTML)
+}
+
+func (a *Arr) format(esc Escaper) string {
 	if len(a.Elements) == 0 {
 		return ""
 	}
 
 	var out bytes.Buffer
 	for _, elem := range a.Elements {
-		out.WriteString(elem.String() + ", ")
+		out.WriteString(Format(elem, esc) + ", ")
 	}
 
 	if out.Len() > 1 {
@@ -3,6 +3,8 @@ package value
 import (
 	"bytes"
 	"fmt"
+	"strings"
+	"time"
 )
 
 var outputHTML = `
@@ -25,6 +27,10 @@ var outputHTML = `
 
 type Dump struct {
 	Vals []Literal
+
+	// Plain makes String return the values as plain text
+	// instead of HTML with inline styles.
+	Plain bool
 }
 
 func NewDump(cap int) *Dump {
@@ -40,12 +46,73 @@ func (d *Dump) String() string {
 	out.Grow(len(d.Vals))
 
 	for _, v := range d.Vals {
+		if d.Plain {
+			out.WriteString(dumpText(v, 0) + "\n")
+			continue
+		}
+
 		fmt.Fprintf(&out, outputHTML, v.Dump(0))
 	}
 
 	return out.String()
 }
 
+// dumpText returns the same output as Literal.Dump without HTML.
+func dumpText(v Literal, indent int) string {
+	spaces := strings.Repeat("  ", indent)
+	insideSpaces := strings.Repeat("  ", indent+1)
+
+	var out strings.Builder
+
+	switch v := v.(type) {
+	case *Str:
+		return fmt.Sprintf("%q", v.Val)
+	case *Nil:
+		return "nil"
+	case *Time:
+		return fmt.Sprintf("time %q", v.Val.Format(time.RFC3339Nano))
+	case *Lambda:
+		return v.signature()
+	case *Error:
+		return fmt.Sprintf("error\"\"\"\n%s\n\n%s\n\"\"\"", v.Err.Meta(), v.Err.Message())
+	case *Arr:
+		fmt.Fprintf(&out, "array:%d [", len(v.Elements))
+
+		if len(v.Elements) == 0 {
+			out.WriteByte(']')
+			return out.String()
+		}
+
+		out.WriteByte('\n')
+
+		for _, elem := range v.Elements {
+			out.WriteString(insideSpaces + dumpText(elem, indent+1) + ",\n")
+		}
+
+		out.WriteString(spaces + "]")
+	case *Obj:
+		fmt.Fprintf(&out, "object:%d {", len(v.Pairs))
+
+		if len(v.Pairs) == 0 {
+			out.WriteByte('}')
+			return out.String()
+		}
+
+		out.WriteByte('\n')
+
+		for _, key := range v.sortedKeys() {
+			pair := dumpText(v.Pairs[key], indent+1)
+			fmt.Fprintf(&out, "%s%q: %s,\n", insideSpaces, key, pair)
+		}
+
+		out.WriteString(spaces + "}")
+	default:
+		return v.String()
+	}
+
+	return out.String()
+}
+
 func (d *Dump) Is(t ValueType) bool {
 	return t == d.Type()
 }
@@ -0,0 +1,52 @@
+package value
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+)
+
+// Escaper escapes strings before they are written to the output.
+// Raw strings are never escaped.
+type Escaper func(s string) string
+
+// formatter is implemented by values that contain strings.
+type formatter interface {
+	format(esc Escaper) string
+}
+
+// Format returns the output of the value like String does,
+// but escapes strings with the given escaper instead of HTML escaping.
+func Format(v Value, esc Escaper) string {
+	if f, ok := v.(formatter); ok {
+		return f.format(esc)
+	}
+	return v.String()
+}
+
+// EscapeHTML escapes special HTML characters like "<" and "&".
+func EscapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// EscapeNone returns the string as it is.
+func EscapeNone(s string) string {
+	return s
+}
+
+// EscapeJSON escapes the string to be placed between quotes
+// of a JSON string, the quotes are not added. Characters "<", ">"
+// and "&" are escaped as well, like encoding/json does.
+func EscapeJSON(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	return string(b[1 : len(b)-1])
+}
+
+// EscapeShell wraps the string in single quotes so that a POSIX shell
+// treats it as a single word.
+func EscapeShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,59 @@
+package value
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LambdaFunc calls the lambda body with the given arguments. The result
+// is an *Error when the body fails.
+type LambdaFunc func(args ...Literal) Literal
+
+// Lambda is an arrow function like `u => u.name`. It captures the scope
+// where it's defined.
+type Lambda struct {
+	Params []string
+	Fn     LambdaFunc
+}
+
+func (*Lambda) Type() ValueType {
+	return LAMBDA_VAL
+}
+
+func (*Lambda) String() string {
+	return ""
+}
+
+func (l *Lambda) Dump(ident int) string {
+	return fmt.Sprintf(`<span style="%s">%s</span>`, DUMP_META, l.signature())
+}
+
+func (*Lambda) JSON() (string, error) {
+	return "null", nil
+}
+
+// Native returns the lambda as a Go function, so that custom functions
+// can call it. It returns an error when the lambda body fails.
+func (l *Lambda) Native() any {
+	return func(args ...any) any {
+		vals := make([]Literal, len(args))
+		for i := range args {
+			vals[i] = NativeToValue(args[i])
+		}
+
+		res := l.Fn(vals...)
+		if err, ok := res.(*Error); ok {
+			return err.Err
+		}
+
+		return res.Native()
+	}
+}
+
+func (l *Lambda) Is(t ValueType) bool {
+	return t == l.Type()
+}
+
+func (l *Lambda) signature() string {
+	return "lambda(" + strings.Join(l.Params, ", ") + ")"
+}
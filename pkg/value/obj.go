@@ -24,6 +24,10 @@ func (*Obj) Type() ValueType {
 }
 
 func (o *Obj) String() string {
+	return o.format(EscapeHTML)
+}
+
+func (o *Obj) format(esc Escaper) string {
 	if o.Pairs == nil {
 		return "{}"
 	}
@@ -41,9 +45,9 @@ func (o *Obj) String() string {
 		}
 
 		if _, isStr := pair.(*Str); isStr {
-			out.WriteString(k + `: "` + pair.String() + `"`)
+			out.WriteString(k + `: "` + Format(pair, esc) + `"`)
 		} else {
-			out.WriteString(k + ": " + pair.String())
+			out.WriteString(k + ": " + Format(pair, esc))
 		}
 	}
 
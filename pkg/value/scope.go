@@ -1,8 +1,7 @@
 package value
 
 import (
-	"errors"
-	"fmt"
+	"maps"
 
 	"github.com/textwire/textwire/v4/pkg/fail"
 )
@@ -29,7 +28,7 @@ func NewScopeFromMap(data map[string]any) (*Scope, *fail.Error) {
 		}
 
 		if err := scope.Set(key, obj); err != nil {
-			return nil, fail.New(nil, "", fail.OriginEval, "%s", err.Error())
+			return nil, fail.FromError(err, nil, "", fail.OriginEval)
 		}
 	}
 
@@ -52,7 +51,7 @@ func (e *Scope) Get(name string) (Literal, bool) {
 
 func (e *Scope) Set(key string, val Literal) error {
 	if key == "loop" || key == "global" {
-		return errors.New(fail.ErrReservedIdentifiers)
+		return fail.Errorf(fail.ErrReservedIdentifiers)
 	}
 
 	if oldVar, ok := e.isTypeMismatch(key, val); ok {
@@ -64,6 +63,31 @@ func (e *Scope) Set(key string, val Literal) error {
 	return nil
 }
 
+// Define sets the variable in this scope without checking the types of
+// variables with the same name in parent scopes, so it can shadow them.
+func (e *Scope) Define(key string, val Literal) error {
+	if key == "loop" || key == "global" {
+		return fail.Errorf(fail.ErrReservedIdentifiers)
+	}
+
+	e.vars[key] = val
+
+	return nil
+}
+
+// Vars returns all variables visible in the scope, including variables
+// of parent scopes. Variables of child scopes shadow parent ones.
+func (e *Scope) Vars() map[string]Literal {
+	vars := map[string]Literal{}
+	if e.parent != nil {
+		vars = e.parent.Vars()
+	}
+
+	maps.Copy(vars, e.vars)
+
+	return vars
+}
+
 func (e *Scope) SetLoopVar(pairs map[string]Literal) {
 	e.vars["loop"] = NewObj(pairs)
 }
@@ -96,6 +120,5 @@ func (e *Scope) isTypeMismatch(key string, val Value) (Value, bool) {
 }
 
 func (e *Scope) identifierMismatchError(key string, oldVar, val Value) error {
-	msg := fmt.Sprintf(fail.ErrIdentTypeMismatch, key, oldVar.Type(), val.Type())
-	return errors.New(msg)
+	return fail.Errorf(fail.ErrIdentTypeMismatch, key, oldVar.Type(), val.Type())
 }
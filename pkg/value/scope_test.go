@@ -349,3 +349,31 @@ func TestAddGlobalData(t *testing.T) {
 		})
 	}
 }
+
+func TestDefineShadowsParentVariable(t *testing.T) {
+	parent := NewScope()
+	if err := parent.Set("x", &Str{Val: "outer"}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	child := parent.Child()
+	if err := child.Set("x", &Int{Val: 1}); err == nil {
+		t.Fatalf("Set must fail when a parent variable has a different type")
+	}
+
+	if err := child.Define("x", &Int{Val: 1}); err != nil {
+		t.Fatalf("Define must not fail, got %s", err)
+	}
+
+	if val, _ := child.Get("x"); val.String() != "1" {
+		t.Fatalf("Expected child 'x' to be 1, got %q", val)
+	}
+
+	if val, _ := parent.Get("x"); val.String() != "outer" {
+		t.Fatalf("Expected parent 'x' to be 'outer', got %q", val)
+	}
+
+	if err := child.Define("loop", &Int{Val: 1}); err == nil {
+		t.Fatalf("Define must fail for reserved names")
+	}
+}
@@ -2,7 +2,6 @@ package value
 
 import (
 	"fmt"
-	"html"
 )
 
 type Str struct {
@@ -15,10 +14,14 @@ func (*Str) Type() ValueType {
 }
 
 func (s *Str) String() string {
+	return s.format(EscapeHTML)
+}
+
+func (s *Str) format(esc Escaper) string {
 	if s.IsRaw {
 		return s.Val
 	}
-	return html.EscapeString(s.Val)
+	return esc(s.Val)
 }
 
 func (s *Str) Dump(ident int) string {
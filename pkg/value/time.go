@@ -0,0 +1,42 @@
+package value
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeLayout is the layout of times converted to strings.
+const TimeLayout = "2006-01-02 15:04:05"
+
+type Time struct {
+	Val time.Time
+}
+
+func (*Time) Type() ValueType {
+	return TIME_VAL
+}
+
+func (t *Time) String() string {
+	return t.Val.Format(TimeLayout)
+}
+
+func (t *Time) Dump(ident int) string {
+	return fmt.Sprintf(
+		`<span style="%s">time </span><span style="%s">%q</span>`,
+		DUMP_META,
+		DUMP_STR,
+		t.Val.Format(time.RFC3339Nano),
+	)
+}
+
+func (t *Time) JSON() (string, error) {
+	return fmt.Sprintf("%q", t.Val.Format(time.RFC3339Nano)), nil
+}
+
+func (t *Time) Native() any {
+	return t.Val
+}
+
+func (t *Time) Is(vt ValueType) bool {
+	return vt == t.Type()
+}
@@ -36,7 +36,7 @@ func NativeToValue(val any) Literal {
 	case uint64:
 		return &Int{Val: int64(v)}
 	case time.Time:
-		return &Str{Val: v.Format("2006-01-02 15:04:05")}
+		return &Time{Val: v}
 	case nil:
 		return new(Nil)
 	}
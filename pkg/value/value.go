@@ -17,6 +17,7 @@ const (
 	STR_VAL   ValueType = "string"
 	ARR_VAL   ValueType = "array"
 	OBJ_VAL   ValueType = "object"
+	TIME_VAL  ValueType = "time"
 
 	TEXT_VAL      ValueType = "text"
 	USE_VAL       ValueType = "layout"
@@ -25,6 +26,7 @@ const (
 	BLOCK_VAL     ValueType = "block"
 	EMBEDDED_VAL  ValueType = "embedded"
 	BUILTIN_VAL   ValueType = "function"
+	LAMBDA_VAL    ValueType = "lambda"
 	COMPONENT_VAL ValueType = "component"
 	SLOT_VAL      ValueType = "slot"
 	DUMP_VAL      ValueType = "dump"
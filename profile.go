@@ -0,0 +1,46 @@
+package textwire
+
+import (
+	"context"
+
+	"github.com/textwire/textwire/v4/pkg/evaluator"
+	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/i18n"
+	"github.com/textwire/textwire/v4/pkg/profile"
+)
+
+// Profile evaluates the template like String does and measures how much
+// time is spent in each @for and @each loop, function call and @component
+// directive. Use Profile.WriteText or Profile.WritePprof to see the report.
+// Hooks are not called while profiling.
+func (t *Template) Profile(name string, data map[string]any) (*profile.Profile, *fail.Error) {
+	return t.ProfileContext(context.Background(), name, data)
+}
+
+// ProfileContext is like Profile, but evaluates the template in the locale
+// set with i18n.WithLocale, the same way StringContext does.
+func (t *Template) ProfileContext(
+	ctx context.Context,
+	name string,
+	data map[string]any,
+) (*profile.Profile, *fail.Error) {
+	prog, scope, failure := t.program(name, data)
+	if failure != nil {
+		return nil, failure
+	}
+
+	rec := profile.NewRecorder(prog.Name)
+	rec.Enter(profile.KindTemplate, prog.Name, prog.AbsPath, nil)
+
+	e := evaluator.New(customFunc, userConf).
+		WithLocale(i18n.LocaleFrom(ctx)).
+		WithOutput(userConf.OutputFor(prog.AbsPath)).
+		WithProfiler(rec)
+	if _, failure := evaluate(e, prog, scope); failure != nil {
+		return nil, failure
+	}
+
+	rec.Exit()
+
+	return rec.Profile(), nil
+}
@@ -0,0 +1,94 @@
+package textwire
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/profile"
+)
+
+func TestTemplateProfile(t *testing.T) {
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "testdata/good/before/each-and-comp",
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	data := map[string]any{"names": []string{"Anna", "Serhii", "Vasyl"}}
+	prof, failure := tpl.Profile("views/index", data)
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	cases := []struct {
+		id    uint
+		kind  profile.Kind
+		name  string
+		calls int
+	}{
+		{1, profile.KindTemplate, "views/index", 1},
+		{2, profile.KindLoop, "@each(name in names)", 1},
+		{3, profile.KindComponent, "components/person", 3},
+	}
+
+	for _, tc := range cases {
+		entries := prof.Hottest(tc.kind, 0)
+		if len(entries) != 1 {
+			t.Fatalf("Case: %d. expected 1 entry, got %d", tc.id, len(entries))
+		}
+
+		entry := entries[0]
+		if entry.Name != tc.name || entry.Calls != tc.calls {
+			t.Fatalf("Case: %d. expected %s called %d times, got %s called %d times",
+				tc.id, tc.name, tc.calls, entry.Name, entry.Calls)
+		}
+
+		if entry.Self > entry.Total {
+			t.Fatalf("Case: %d. self time %s is more than total %s", tc.id, entry.Self, entry.Total)
+		}
+	}
+
+	if entries := prof.Hottest(profile.KindComponent, -1); len(entries) != 1 {
+		t.Fatalf("expected 1 entry for negative n, got %d", len(entries))
+	}
+
+	loop := prof.Hottest(profile.KindLoop, 1)[0]
+	if !strings.HasSuffix(loop.Location(), "each-and-comp/views/index.tw:1") {
+		t.Fatalf("wrong loop location %q", loop.Location())
+	}
+
+	text := &strings.Builder{}
+	if err := prof.WriteText(text, 10); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, str := range []string{"Loops:", "Components:", "@each(name in names)"} {
+		if !strings.Contains(text.String(), str) {
+			t.Fatalf("expected text report to contain %q, got:\n%s", str, text)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := prof.WritePprof(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("pprof output is not gzipped: %s", err)
+	}
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !bytes.Contains(raw, []byte("components/person")) {
+		t.Fatalf("expected pprof output to contain function names")
+	}
+}
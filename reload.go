@@ -0,0 +1,163 @@
+package textwire
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/fail"
+)
+
+//go:embed embed/live-reload.tw
+var liveReloadScript string
+
+// reloadHub notifies subscribers when the file watcher relinks programs.
+type reloadHub struct {
+	mu        sync.Mutex
+	callbacks []func(failure *fail.Error)
+	clients   map[chan *fail.Error]struct{}
+}
+
+func newReloadHub() *reloadHub {
+	return &reloadHub{
+		clients: map[chan *fail.Error]struct{}{},
+	}
+}
+
+// onReload registers a callback that is called after every reload.
+func (h *reloadHub) onReload(fn func(failure *fail.Error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.callbacks = append(h.callbacks, fn)
+}
+
+// notify calls registered callbacks and sends failure to every subscribed
+// client. Clients that are not ready to receive are skipped because they
+// still have a pending notification.
+func (h *reloadHub) notify(failure *fail.Error) {
+	h.mu.Lock()
+	callbacks := h.callbacks
+	for ch := range h.clients {
+		select {
+		case ch <- failure:
+		default:
+		}
+	}
+	h.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(failure)
+	}
+}
+
+// subscribe returns a channel that receives notifications and a function
+// that must be called to unsubscribe.
+func (h *reloadHub) subscribe() (<-chan *fail.Error, func()) {
+	ch := make(chan *fail.Error, 1)
+
+	h.mu.Lock()
+	h.clients[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.clients, ch)
+		h.mu.Unlock()
+	}
+}
+
+// OnReload registers a callback that is called every time the file watcher
+// reparses and relinks templates. The failure is nil when all templates are
+// valid. Callbacks are only called when config.FileWatcher is enabled.
+func (t *Template) OnReload(fn func(failure *fail.Error)) {
+	t.reload.onReload(fn)
+}
+
+// LiveReloadHandler returns an http.Handler that streams reload
+// notifications to the browser using Server-Sent Events. Mount it on
+// config.LiveReloadPath to enable live reloading in DebugMode.
+func (t *Template) LiveReloadHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		ch, unsubscribe := t.reload.subscribe()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+
+		t.linker.RLock()
+		linkErr := t.linker.LinkError
+		t.linker.RUnlock()
+
+		if linkErr != nil {
+			writeReloadEvent(w, linkErr)
+		} else {
+			_, _ = fmt.Fprint(w, ": connected\n\n")
+		}
+
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case failure := <-ch:
+				writeReloadEvent(w, failure)
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// writeReloadEvent writes a "reload" event when failure is nil and
+// a "failure" event with error details otherwise.
+func writeReloadEvent(w http.ResponseWriter, failure *fail.Error) {
+	if failure == nil {
+		_, _ = fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+		return
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"path":    failure.Filepath(),
+		"line":    failure.Pos().Line(),
+		"col":     failure.Pos().Col(),
+		"message": failure.Message(),
+	})
+	if err != nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "event: failure\ndata: %s\n\n", data)
+}
+
+// usesLiveReload returns true when live reload script should be injected.
+func usesLiveReload() bool {
+	return userConf.DebugMode && userConf.FileWatcher && userConf.LiveReloadPath != ""
+}
+
+// injectLiveReload adds the live reload script before the closing body
+// tag of the given HTML. The script is appended when there is no body tag.
+func injectLiveReload(html string) (string, *fail.Error) {
+	data := map[string]any{"path": userConf.LiveReloadPath}
+	script, failure := evaluateString(liveReloadScript, data, config.OutputHTML)
+	if failure != nil {
+		return "", failure
+	}
+
+	idx := strings.LastIndex(html, "</body>")
+	if idx == -1 {
+		return html + script, nil
+	}
+
+	return html[:idx] + script + html[idx:], nil
+}
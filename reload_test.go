@@ -0,0 +1,109 @@
+package textwire
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/textwire/textwire/v4/pkg/fail"
+	"github.com/textwire/textwire/v4/pkg/linker"
+	"github.com/textwire/textwire/v4/pkg/position"
+)
+
+func TestInjectLiveReload(t *testing.T) {
+	oldPath := userConf.LiveReloadPath
+	userConf.LiveReloadPath = "/__reload"
+	defer func() { userConf.LiveReloadPath = oldPath }()
+
+	cases := []struct {
+		name   string
+		inp    string
+		prefix string
+		suffix string
+	}{
+		{
+			"with body",
+			"<body><h1>Hi</h1></body></html>",
+			"<body><h1>Hi</h1><script>",
+			"</script>\n</body></html>",
+		},
+		{"without body", "<h1>Hi</h1>", "<h1>Hi</h1><script>", "</script>\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, failure := injectLiveReload(tc.inp)
+			if failure != nil {
+				t.Fatalf("unexpected error: %s", failure)
+			}
+
+			if !strings.HasPrefix(out, tc.prefix) || !strings.HasSuffix(out, tc.suffix) {
+				t.Fatalf("script is injected in the wrong place:\n%s", out)
+			}
+
+			if !strings.Contains(out, `new EventSource("/__reload")`) {
+				t.Fatalf("script doesn't contain reload path:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestLiveReloadHandler(t *testing.T) {
+	tpl := &Template{linker: linker.New(nil), reload: newReloadHub()}
+
+	var calls int
+	tpl.OnReload(func(*fail.Error) { calls++ })
+
+	srv := httptest.NewServer(tpl.LiveReloadHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected event stream content type, got %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		t.Helper()
+		var event strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("unexpected error reading event: %s", err)
+			}
+			if line == "\n" {
+				return event.String()
+			}
+			event.WriteString(line)
+		}
+	}
+
+	if event := readEvent(); event != ": connected\n" {
+		t.Fatalf("expected connected comment, got %q", event)
+	}
+
+	tpl.reload.notify(nil)
+	if event := readEvent(); event != "event: reload\ndata: {}\n" {
+		t.Fatalf("expected reload event, got %q", event)
+	}
+
+	failure := fail.New(&position.Pos{StartLine: 2}, "/tpl/index.tw", fail.OriginPars, "oops")
+	tpl.reload.notify(failure)
+
+	expect := "event: failure\n" +
+		`data: {"col":1,"line":3,"message":"oops","path":"/tpl/index.tw"}` + "\n"
+	if event := readEvent(); event != expect {
+		t.Fatalf("expected failure event %q, got %q", expect, event)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected OnReload callback to be called 2 times, got %d", calls)
+	}
+}
@@ -1,14 +1,19 @@
 package textwire
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/textwire/textwire/v4/config"
 	"github.com/textwire/textwire/v4/pkg/ast"
 	"github.com/textwire/textwire/v4/pkg/evaluator"
 	"github.com/textwire/textwire/v4/pkg/fail"
 	"github.com/textwire/textwire/v4/pkg/file"
+	"github.com/textwire/textwire/v4/pkg/hooks"
+	"github.com/textwire/textwire/v4/pkg/i18n"
 	"github.com/textwire/textwire/v4/pkg/linker"
 	"github.com/textwire/textwire/v4/pkg/value"
 )
@@ -16,7 +21,10 @@ import (
 // Template holds all necessary data which it will use when individual
 // template files will be evaluated by String() or Response() methods.
 type Template struct {
-	linker *linker.NodeLinker
+	linker  *linker.NodeLinker
+	reload  *reloadHub
+	hooks   hooks.Hooks
+	watcher *fileWatcher
 }
 
 // NewTemplate returns a new Template instance with parsed Textwire files
@@ -40,40 +48,110 @@ func NewTemplate(opt *config.Config) (*Template, *fail.Error) {
 		return nil, failure
 	}
 
-	tpl := &Template{linker: ln}
+	tpl := &Template{linker: ln, reload: newReloadHub()}
 
 	if opt.FileWatcher {
-		newFileWatcher(ln).Watch()
+		tpl.watcher = newFileWatcher(ln, tpl.reload.notify)
+		if err := tpl.watcher.Watch(); err != nil {
+			_ = tpl.watcher.Close()
+			return nil, fail.FromError(err, nil, "", fail.OriginTpl)
+		}
+
+		runningWatcher = tpl.watcher
 	}
 
 	return tpl, nil
 }
 
+// Close stops the file watcher when FileWatcher is enabled. Call it when
+// the server shuts down. The watcher is also stopped when templates are
+// configured again with Configure or NewTemplate.
+func (t *Template) Close() error {
+	if t.watcher == nil {
+		return nil
+	}
+
+	return t.watcher.Close()
+}
+
+// SetHooks sets hooks that are notified when templates, components and
+// functions are rendered. Use hooks.Multi to set multiple hooks and
+// hooks.Tracing to emit spans. Call it before rendering any templates.
+func (t *Template) SetHooks(h hooks.Hooks) {
+	t.hooks = h
+}
+
 // String returns final evaluated template result represented as a string.
 func (t *Template) String(name string, data map[string]any) (string, *fail.Error) {
+	return t.StringContext(context.Background(), name, data)
+}
+
+// StringContext is like String, but passes ctx to hooks. Use it to make
+// template spans children of your request span and to translate messages
+// to the locale set with i18n.WithLocale.
+func (t *Template) StringContext(
+	ctx context.Context,
+	name string,
+	data map[string]any,
+) (string, *fail.Error) {
+	prog, scope, failure := t.program(name, data)
+	if failure != nil {
+		return "", failure
+	}
+
+	e := evaluator.New(customFunc, userConf).
+		WithLocale(i18n.LocaleFrom(ctx)).
+		WithOutput(userConf.OutputFor(prog.AbsPath))
+	if t.hooks == nil {
+		return evaluate(e, prog, scope)
+	}
+
+	event := hooks.Render{Template: prog.Name, Path: prog.AbsPath, Start: time.Now()}
+	ctx = t.hooks.OnRenderStart(ctx, event)
+
+	out, failure := evaluate(e.WithHooks(ctx, t.hooks), prog, scope)
+	event.Duration = time.Since(event.Start)
+	event.Size = len(out)
+	if failure != nil {
+		event.Err = failure
+	}
+
+	t.hooks.OnRenderEnd(ctx, event)
+
+	return out, failure
+}
+
+// program returns the linked program with the given name
+// and the scope with the given data.
+func (t *Template) program(
+	name string,
+	data map[string]any,
+) (*ast.Program, *value.Scope, *fail.Error) {
 	t.linker.RLock()
 	linkErr, progs := t.linker.LinkError, t.linker.Programs
 	t.linker.RUnlock()
 
 	if linkErr != nil {
-		return "", linkErr
+		return nil, nil, linkErr
 	}
 
 	scope, err := value.NewScopeFromMap(data)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
 	name = file.ReplacePathAlias(name, file.PathAliasViews)
 	prog := ast.FindProg(name, progs)
 	if prog == nil {
 		relPath := file.NameToRelPath(name, userConf.TemplateDir, userConf.TemplateExt)
-		return "", fail.New(nil, relPath, fail.OriginTpl, fail.ErrTemplateNotFound, name)
+		return nil, nil, fail.New(nil, relPath, fail.OriginTpl, fail.ErrTemplateNotFound, name)
 	}
 
-	e := evaluator.New(customFunc, userConf)
-	ctx := evaluator.NewContext(scope, prog.AbsPath)
-	evaluated := e.Eval(prog, ctx)
+	return prog, scope, nil
+}
+
+func evaluate(e *evaluator.Evaluator, prog *ast.Program, scope *value.Scope) (string, *fail.Error) {
+	evaluated := e.Eval(prog, evaluator.NewContext(scope, prog.AbsPath))
 	if evaluated.Is(value.ERR_VAL) {
 		return "", evaluated.(*value.Error).Err
 	}
@@ -85,6 +163,10 @@ func (t *Template) String(name string, data map[string]any) (string, *fail.Error
 // string to the given http.ResponseWriter.
 func (t *Template) Response(w http.ResponseWriter, name string, data map[string]any) *fail.Error {
 	evaluated, failure := t.String(name, data)
+	if failure == nil && userConf.OutputFor(name) == config.OutputHTML {
+		evaluated, failure = t.withLiveReload(evaluated)
+	}
+
 	if failure == nil {
 		_, err := fmt.Fprint(w, evaluated)
 		if err != nil {
@@ -94,16 +176,24 @@ func (t *Template) Response(w http.ResponseWriter, name string, data map[string]
 		return nil
 	}
 
+	logFailure(slog.LevelError, "template evaluation failed", failure)
+
 	hasErrPage := userConf.ErrorPagePath != ""
 	if hasErrPage && !userConf.DebugMode {
 		if err := t.responseErrorPage(w); err != nil {
+			logFailure(slog.LevelError, "custom error page failed", err)
 			return err
 		}
 
 		return failure
 	}
 
-	errPage, err := errorPage(failure)
+	errPage, err := t.errorPage(failure)
+	if err != nil {
+		return err
+	}
+
+	errPage, err = t.withLiveReload(errPage)
 	if err != nil {
 		return err
 	}
@@ -129,3 +219,13 @@ func (t *Template) responseErrorPage(w http.ResponseWriter) *fail.Error {
 
 	return nil
 }
+
+// withLiveReload injects live reload script into the HTML when it's enabled.
+// It must not be called for pages with other output modes.
+func (t *Template) withLiveReload(html string) (string, *fail.Error) {
+	if !usesLiveReload() {
+		return html, nil
+	}
+
+	return injectLiveReload(html)
+}
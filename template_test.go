@@ -1,11 +1,14 @@
 package textwire
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/textwire/textwire/v4/config"
 	"github.com/textwire/textwire/v4/pkg/fail"
@@ -482,3 +485,216 @@ func TestTwoTemplates(t *testing.T) {
 		t.Errorf("Wrong result for about.tw. Expect:\n'%s'\ngot:\n'%s'", expectAbout, actualAbout)
 	}
 }
+
+func TestErrorStack(t *testing.T) {
+	absPath, err := file.ToFullPath("")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	absPath += "/testdata/bad/"
+
+	type frame struct {
+		kind fail.FrameKind
+		name string
+		path string
+		line uint
+	}
+
+	cases := []struct {
+		dir    string
+		data   map[string]any
+		expect []frame
+	}{
+		{
+			dir:  "undefined-var-in-nested-comp",
+			data: map[string]any{"name": "Amy"},
+			expect: []frame{
+				{fail.FrameComponent, "second", "undefined-var-in-nested-comp/first.tw", 1},
+				{fail.FrameComponent, "first", "undefined-var-in-nested-comp/index.tw", 1},
+				{fail.FramePage, "index", "undefined-var-in-nested-comp/index.tw", 0},
+			},
+		},
+		{
+			dir: "error-in-slot",
+			expect: []frame{
+				{fail.FrameSlot, "title", "error-in-slot/index.tw", 5},
+				{fail.FrameComponent, "card", "error-in-slot/index.tw", 4},
+				{fail.FrameInsert, "content", "error-in-slot/index.tw", 3},
+				{fail.FrameLayout, "layout", "error-in-slot/index.tw", 1},
+				{fail.FramePage, "index", "error-in-slot/index.tw", 0},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.dir, func(t *testing.T) {
+			tpl, failure := NewTemplate(&config.Config{TemplateDir: "testdata/bad/" + tc.dir})
+			if failure != nil {
+				t.Fatalf("unexpected error: %s", failure)
+			}
+
+			_, failure = tpl.String("index", tc.data)
+			if failure == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			stack := failure.Stack()
+			if len(stack) != len(tc.expect) {
+				t.Fatalf("expected %d frames, got:\n%s", len(tc.expect), failure.StackTrace())
+			}
+
+			for i, expect := range tc.expect {
+				got := stack[i]
+				line := uint(0)
+				if expect.kind != fail.FramePage {
+					line = got.Pos.Line()
+				}
+
+				if got.Kind != expect.kind || got.Name != expect.name ||
+					got.Filepath != absPath+expect.path || line != expect.line {
+					t.Fatalf("wrong frame %d, expect %+v, got:\n%s", i, expect, got)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorPageDetails(t *testing.T) {
+	cases := []struct {
+		name      string
+		debugMode bool
+		contains  []string
+		excludes  []string
+	}{
+		{
+			name:      "debug mode",
+			debugMode: true,
+			contains: []string{
+				`<span class="num">5</span>        @pass(&#39;title&#39;){{ missing }}@end</div>`,
+				`<span class="num"></span>                         ^^^^^^^</div>`,
+				`<span class="num">3</span>@insert(&#39;content&#39;)</div>`,
+				"<th>count</th>\n",
+				"<td>3</td>",
+				"<b>slot</b> title",
+			},
+		},
+		{
+			name:      "production mode",
+			debugMode: false,
+			excludes:  []string{"missing", "count", "<b>slot</b>", `class="panel`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tpl, failure := NewTemplate(&config.Config{
+				TemplateDir: "testdata/bad/error-in-slot",
+				DebugMode:   tc.debugMode,
+			})
+			if failure != nil {
+				t.Fatalf("unexpected error: %s", failure)
+			}
+
+			w := httptest.NewRecorder()
+			if failure := tpl.Response(w, "index", map[string]any{"count": 3}); failure == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			body := w.Body.String()
+			for _, str := range tc.contains {
+				if !strings.Contains(body, str) {
+					t.Fatalf("expected error page to contain %q, got:\n%s", str, body)
+				}
+			}
+
+			for _, str := range tc.excludes {
+				if strings.Contains(body, str) {
+					t.Fatalf("expected error page not to contain %q, got:\n%s", str, body)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateLogger(t *testing.T) {
+	t.Run("undefined variable warning", func(t *testing.T) {
+		var out strings.Builder
+		tpl, failure := NewTemplate(&config.Config{
+			TemplateDir: "testdata/bad/error-in-slot",
+			Undefined:   config.UndefinedEmpty,
+			Logger:      slog.New(slog.NewJSONHandler(&out, nil)),
+		})
+		if failure != nil {
+			t.Fatalf("unexpected error: %s", failure)
+		}
+
+		defer Configure(&config.Config{})
+
+		if _, failure := tpl.String("index", nil); failure != nil {
+			t.Fatalf("unexpected error: %s", failure)
+		}
+
+		var record map[string]any
+		if err := json.Unmarshal([]byte(out.String()), &record); err != nil {
+			t.Fatalf("expected one JSON record, got %q: %s", out.String(), err)
+		}
+
+		expect := map[string]any{
+			"level":    "WARN",
+			"msg":      "undefined variable",
+			"code":     string(fail.CodeVariableIsUndefined),
+			"template": "index",
+			"line":     float64(5),
+		}
+
+		for key, val := range expect {
+			if record[key] != val {
+				t.Fatalf("expected %s to be %v, got %v", key, val, record[key])
+			}
+		}
+
+		if path, _ := record["path"].(string); !strings.HasSuffix(path, "error-in-slot/index.tw") {
+			t.Fatalf("expected path to index.tw, got %q", path)
+		}
+	})
+
+	t.Run("broken custom error page", func(t *testing.T) {
+		var out strings.Builder
+		tpl, failure := NewTemplate(&config.Config{
+			TemplateDir: "templates",
+			TemplateFS: fstest.MapFS{
+				"templates/index.tw": {Data: []byte("{{ missing }}")},
+				"templates/error.tw": {Data: []byte("{{ alsoMissing }}")},
+			},
+			ErrorPagePath: "error",
+			Logger:        slog.New(slog.NewTextHandler(&out, nil)),
+		})
+		if failure != nil {
+			t.Fatalf("unexpected error: %s", failure)
+		}
+
+		defer Configure(&config.Config{})
+
+		w := httptest.NewRecorder()
+		failure = tpl.Response(w, "index", nil)
+		if failure == nil || !strings.Contains(failure.Error(), "alsoMissing") {
+			t.Fatalf("expected error of the custom error page, got %v", failure)
+		}
+
+		if w.Body.Len() != 0 {
+			t.Fatalf("expected empty response, got:\n%s", w.Body.String())
+		}
+
+		logs := out.String()
+		for _, str := range []string{
+			`msg="template evaluation failed"`,
+			`msg="custom error page failed"`,
+			`template=error`,
+		} {
+			if !strings.Contains(logs, str) {
+				t.Fatalf("expected logs to contain %q, got:\n%s", str, logs)
+			}
+		}
+	})
+}
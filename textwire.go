@@ -11,12 +11,25 @@ import (
 var (
 	userConf   = config.New()
 	customFunc = config.NewFunc()
+
+	// runningWatcher is the file watcher started by the last NewTemplate
+	// call. It's closed when the config changes.
+	runningWatcher *fileWatcher
 )
 
 // EvaluateString evaluates a given inp string containing Textwire code.
 // The function accepts a string template and data to inject into Textwire.
 // After evaluation, it returns the processed string and any error encountered.
+// Values are escaped according to Output from the config.
 func EvaluateString(inp string, data map[string]any) (string, *fail.Error) {
+	return evaluateString(inp, data, userConf.Output)
+}
+
+func evaluateString(
+	inp string,
+	data map[string]any,
+	mode config.OutputMode,
+) (string, *fail.Error) {
 	prog, errs := parseStr(inp)
 	if len(errs) != 0 {
 		return "", errs[0]
@@ -27,7 +40,7 @@ func EvaluateString(inp string, data map[string]any) (string, *fail.Error) {
 		return "", err
 	}
 
-	e := evaluator.New(customFunc, nil)
+	e := evaluator.New(customFunc, nil).WithOutput(mode)
 	ctx := evaluator.NewContext(scope, prog.AbsPath)
 	evaluated := e.Eval(prog, ctx)
 	if evaluated.Is(value.ERR_VAL) {
@@ -41,6 +54,7 @@ func EvaluateString(inp string, data map[string]any) (string, *fail.Error) {
 //
 // The absPath an absolute path to the Textwire file.
 // The data is a map of variables you want to inject into the Textwire.
+// Values are escaped according to the file extension, like Template does.
 func EvaluateFile(absPath string, data map[string]any) (string, *fail.Error) {
 	f := file.New("", "", absPath, userConf)
 	content, err := f.Content()
@@ -48,7 +62,7 @@ func EvaluateFile(absPath string, data map[string]any) (string, *fail.Error) {
 		return "", fail.FromError(err, nil, absPath, fail.OriginTpl)
 	}
 
-	res, failure := EvaluateString(content, data)
+	res, failure := evaluateString(content, data, userConf.OutputFor(absPath))
 	if failure != nil {
 		return "", failure
 	}
@@ -64,6 +78,10 @@ func RegisterStrFunc(name string, fn config.StrCustomFunc) *fail.Error {
 		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncAlreadyDefined, name, "strings")
 	}
 
+	if evaluator.IsBuiltinFunc(value.STR_VAL, name) {
+		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncIsBuiltIn, name, "strings")
+	}
+
 	customFunc.Str[name] = fn
 
 	return nil
@@ -77,6 +95,10 @@ func RegisterArrFunc(name string, fn config.ArrCustomFunc) *fail.Error {
 		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncAlreadyDefined, name, "arrays")
 	}
 
+	if evaluator.IsBuiltinFunc(value.ARR_VAL, name) {
+		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncIsBuiltIn, name, "arrays")
+	}
+
 	customFunc.Arr[name] = fn
 
 	return nil
@@ -90,6 +112,10 @@ func RegisterObjFunc(name string, fn config.ObjCustomFunc) *fail.Error {
 		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncAlreadyDefined, name, "objects")
 	}
 
+	if evaluator.IsBuiltinFunc(value.OBJ_VAL, name) {
+		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncIsBuiltIn, name, "objects")
+	}
+
 	customFunc.Obj[name] = fn
 
 	return nil
@@ -103,6 +129,10 @@ func RegisterIntFunc(name string, fn config.IntCustomFunc) *fail.Error {
 		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncAlreadyDefined, name, "integers")
 	}
 
+	if evaluator.IsBuiltinFunc(value.INT_VAL, name) {
+		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncIsBuiltIn, name, "integers")
+	}
+
 	customFunc.Int[name] = fn
 
 	return nil
@@ -116,6 +146,10 @@ func RegisterFloatFunc(name string, fn config.FloatCustomFunc) *fail.Error {
 		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncAlreadyDefined, name, "floats")
 	}
 
+	if evaluator.IsBuiltinFunc(value.FLOAT_VAL, name) {
+		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncIsBuiltIn, name, "floats")
+	}
+
 	customFunc.Float[name] = fn
 
 	return nil
@@ -129,12 +163,24 @@ func RegisterBoolFunc(name string, fn config.BoolCustomFunc) *fail.Error {
 		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncAlreadyDefined, name, "booleans")
 	}
 
+	if evaluator.IsBuiltinFunc(value.BOOL_VAL, name) {
+		return fail.New(nil, "", fail.OriginTpl, fail.ErrFuncIsBuiltIn, name, "booleans")
+	}
+
 	customFunc.Bool[name] = fn
 
 	return nil
 }
 
 // Configure passes given options to the user configurations.
+// It stops the file watcher started by the previous NewTemplate call.
 func Configure(opt *config.Config) {
+	if runningWatcher != nil {
+		if err := runningWatcher.Close(); err != nil {
+			userConf.Log().Warn("cannot stop file watcher", "error", err)
+		}
+		runningWatcher = nil
+	}
+
 	userConf.Configure(opt)
 }
@@ -1,12 +1,16 @@
 package textwire
 
 import (
+	"errors"
 	"io"
+	"io/fs"
 	"log"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/textwire/textwire/v4/config"
 	"github.com/textwire/textwire/v4/pkg/fail"
 	"github.com/textwire/textwire/v4/pkg/file"
 	"github.com/textwire/textwire/v4/pkg/value"
@@ -684,6 +688,23 @@ func TestEvaluateFile(t *testing.T) {
 	}
 }
 
+func TestTrimDirectiveLines(t *testing.T) {
+	Configure(&config.Config{TrimDirectiveLines: true})
+	defer Configure(&config.Config{})
+
+	inp := "<ul>\n  @each(x in [1, 2])\n    <li>{{- x -}}</li>\n  @end\n</ul>\n"
+	expect := "<ul>\n    <li>1</li>\n    <li>2</li>\n</ul>\n"
+
+	actual, err := EvaluateString(inp, nil)
+	if err != nil {
+		t.Fatalf("Error evaluating string: %s", err)
+	}
+
+	if actual != expect {
+		t.Errorf("Wrong output. Expect:\n%q\ngot:\n%q", expect, actual)
+	}
+}
+
 func TestCustomFunctions(t *testing.T) {
 	t.Run("register for integer receiver", func(t *testing.T) {
 		err := RegisterIntFunc("_double", func(num int, args ...any) any {
@@ -744,6 +765,32 @@ func TestCustomFunctions(t *testing.T) {
 		}
 	})
 
+	t.Run("register for array receiver with lambda argument", func(t *testing.T) {
+		err := RegisterArrFunc("_count", func(arr []any, args ...any) any {
+			matches := args[0].(func(args ...any) any)
+			count := 0
+			for _, elem := range arr {
+				if matches(elem) == true {
+					count++
+				}
+			}
+			return count
+		})
+
+		if err != nil {
+			t.Fatalf("Error registering function: %s", err)
+		}
+
+		actual, err := EvaluateString("{{ [1, 5, 8]._count(n => n > 2) }}", nil)
+		if err != nil {
+			t.Fatalf("Error evaluating template: %s", err)
+		}
+
+		if actual != "2" {
+			t.Fatalf("Wrong result. Expect '2' got '%s'", actual)
+		}
+	})
+
 	t.Run("register for object receiver", func(t *testing.T) {
 		err := RegisterObjFunc("_addProp", func(obj map[string]any, args ...any) any {
 			key := args[0].(string)
@@ -832,24 +879,106 @@ func TestCustomFunctions(t *testing.T) {
 		}
 	})
 
-	t.Run("redefining built-in function not working", func(t *testing.T) {
-		err := RegisterStrFunc("trim", func(s string, args ...any) any {
+	t.Run("redefining built-in function fails", func(t *testing.T) {
+		cases := []struct {
+			id   uint
+			name string
+		}{
+			{1, "trim"},
+			{2, "slug"},
+			{3, "nl2br"},
+		}
+
+		for _, tc := range cases {
+			err := RegisterStrFunc(tc.name, func(s string, args ...any) any {
+				return "some output"
+			})
+			if err == nil {
+				t.Fatalf("Case: %d. expect error but got none", tc.id)
+			}
+
+			expect := fail.New(nil, "", fail.OriginTpl, fail.ErrFuncIsBuiltIn, tc.name, "strings")
+			if err := compareFailures(err, expect); err != nil {
+				t.Fatalf("Case: %d. %s", tc.id, err)
+			}
+		}
+
+		err := RegisterIntFunc("format", func(n int, args ...any) any {
 			return "some output"
 		})
+		if err == nil {
+			t.Fatal("Expect error but got none")
+		}
 
-		if err != nil {
-			t.Fatalf("Error registering function: %s", err)
+		expect := fail.New(nil, "", fail.OriginTpl, fail.ErrFuncIsBuiltIn, "format", "integers")
+		if err := compareFailures(err, expect); err != nil {
+			t.Fatal(err)
 		}
 
 		actual, err := EvaluateString("{{ ' anna '.trim() }}", nil)
 		if err != nil {
-			t.Fatalf("Error registering function: %s", err)
+			t.Fatalf("Unexpected error: %s", err)
 		}
 
-		// the output should be the same as the built-in function
-		// even though we redefined it.
 		if actual != "anna" {
 			t.Fatalf("Wrong output. Expect 'anna' but got '%s'", actual)
 		}
 	})
 }
+
+func TestErrorCodes(t *testing.T) {
+	errCustom := errors.New("custom failure")
+	if err := RegisterStrFunc("_fails", func(s string, args ...any) any {
+		return errCustom
+	}); err != nil {
+		t.Fatalf("error registering function: %s", err)
+	}
+
+	cases := []struct {
+		id     uint
+		inp    string
+		code   fail.Code
+		target error
+	}{
+		{1, "{{ undefinedVar }}", fail.CodeVariableIsUndefined, fail.CodeVariableIsUndefined},
+		{2, "{{ 1 / 0 }}", fail.CodeDivisionByZero, fail.CodeDivisionByZero},
+		{3, "{{ [1, 2].slice() }}", fail.CodeFuncMissingArg, fail.CodeFuncMissingArg},
+		{4, "{{ 'nice'._fails() }}", fail.CodeCustomFuncFailed, errCustom},
+		{5, "{{ x = 1; x = 'str' }}", fail.CodeIdentTypeMismatch, fail.CodeIdentTypeMismatch},
+		{6, "{{ }}", fail.CodeEmptyBraces, fail.CodeEmptyBraces},
+	}
+
+	for _, tc := range cases {
+		_, failure := EvaluateString(tc.inp, nil)
+		if failure == nil {
+			t.Fatalf("Case: %d. expected error, got nil", tc.id)
+		}
+
+		if failure.Code() != tc.code {
+			t.Fatalf("Case: %d. expected code %q, got %q", tc.id, tc.code, failure.Code())
+		}
+
+		var err error = failure
+		if !errors.Is(err, tc.target) {
+			t.Fatalf("Case: %d. expected errors.Is(%v, %v) to be true", tc.id, err, tc.target)
+		}
+	}
+
+	t.Run("keeps percent signs of wrapped errors", func(t *testing.T) {
+		_, failure := EvaluateFile("testdata/100%d.tw", nil)
+		if failure == nil || !strings.Contains(failure.Message(), "100%d.tw:") {
+			t.Fatalf("expected message to contain the file name, got %v", failure)
+		}
+	})
+
+	t.Run("unwraps I/O errors", func(t *testing.T) {
+		_, failure := EvaluateFile("testdata/missing.tw", nil)
+		if !errors.Is(failure, fs.ErrNotExist) {
+			t.Fatalf("expected error to wrap fs.ErrNotExist, got %v", failure)
+		}
+
+		if failure.Code() != fail.CodeUnknown {
+			t.Fatalf("expected code %q, got %q", fail.CodeUnknown, failure.Code())
+		}
+	})
+}
@@ -1,7 +1,6 @@
 package textwire
 
 import (
-	_ "embed"
 	"io/fs"
 	"path/filepath"
 	"strings"
@@ -13,30 +12,8 @@ import (
 	"github.com/textwire/textwire/v4/pkg/parser"
 )
 
-//go:embed embed/default-error-page.tw
-var defaultErrPage string
-
-// errorPage returns HTML that's displayed when an error occurs while
-// rendering template.
-func errorPage(failure *fail.Error) (string, *fail.Error) {
-	data := map[string]any{
-		"path":      failure.Filepath(),
-		"line":      failure.Pos().Line(),
-		"col":       failure.Pos().Col(),
-		"message":   failure.Message(),
-		"debugMode": userConf.DebugMode,
-	}
-
-	out, err := EvaluateString(defaultErrPage, data)
-	if err != nil {
-		return "", err
-	}
-
-	return out, nil
-}
-
 func parseStr(text string) (*ast.Program, []*fail.Error) {
-	l := lexer.New(text)
+	l := newLexer(text)
 	p := parser.New(l, nil)
 
 	prog := p.ParseProgram()
@@ -47,6 +24,16 @@ func parseStr(text string) (*ast.Program, []*fail.Error) {
 	return prog, nil
 }
 
+// newLexer creates a lexer for the given template content using
+// the user configuration.
+func newLexer(content string) *lexer.Lexer {
+	l := lexer.New(content)
+	if userConf.TrimDirectiveLines {
+		l.TrimDirectiveLines()
+	}
+	return l
+}
+
 // parseFiles parses each Textwire file into AST nodes and returns them.
 func parseFiles(files []*file.SourceFile) ([]*ast.Program, *fail.Error) {
 	programs := make([]*ast.Program, 0, len(files))
@@ -60,6 +47,7 @@ func parseFiles(files []*file.SourceFile) ([]*ast.Program, *fail.Error) {
 			return programs, failure
 		}
 
+		userConf.Log().Debug("template loaded", "template", f.Name, "path", f.Abs)
 		programs = append(programs, prog)
 	}
 
@@ -73,7 +61,7 @@ func parseFile(f *file.SourceFile) (*ast.Program, *fail.Error, error) {
 		return nil, nil, err
 	}
 
-	l := lexer.New(content)
+	l := newLexer(content)
 	p := parser.New(l, f)
 	if p.HasErrors() {
 		return nil, p.Errors()[0], nil
@@ -82,6 +70,7 @@ func parseFile(f *file.SourceFile) (*ast.Program, *fail.Error, error) {
 	prog := p.ParseProgram()
 	prog.AbsPath = f.Abs
 	prog.Name = f.Name
+	prog.Source = content
 
 	if p.HasErrors() {
 		return nil, p.Errors()[0], nil
@@ -115,6 +104,8 @@ func locateFiles() ([]*file.SourceFile, error) {
 				return nil
 			}
 
+			fsPath := path
+
 			// When using config.TemplateFS to embed templates into binary,
 			// we need to exclude config.TemplateDir from path since it
 			// already contains it.
@@ -136,6 +127,7 @@ func locateFiles() ([]*file.SourceFile, error) {
 				return err
 			}
 
+			file.Path = fsPath
 			file.ModTime = fileInfo.ModTime()
 			files = append(files, file)
 
@@ -1,11 +1,14 @@
 package textwire
 
 import (
-	"os"
-	"path/filepath"
-	"strings"
-	"time"
-
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/textwire/textwire/v4/config"
 	"github.com/textwire/textwire/v4/pkg/ast"
 	"github.com/textwire/textwire/v4/pkg/fail"
 	"github.com/textwire/textwire/v4/pkg/file"
@@ -16,140 +19,179 @@ import (
 // It is designed for development use only due to performance implications.
 type fileWatcher struct {
 	linker    *linker.NodeLinker
-	logger    *WatcherLogger
-	ticker    *time.Ticker
 	files     []*file.SourceFile
-	fileCount int
 	lastError string
+
+	// mu serializes change handling because custom watchers
+	// can report changes from multiple goroutines.
+	mu sync.Mutex
+
+	// changed is true when files were reparsed or removed since
+	// the last time programs were relinked.
+	changed bool
+
+	// parseErrors holds the last parse error of each file by its name.
+	// The previous program of that file is kept until the error is fixed.
+	parseErrors map[string]*fail.Error
+
+	// onReload is called after programs were relinked with the linking
+	// or parsing error, if any. It can be nil.
+	onReload func(failure *fail.Error)
+
+	// watcher reports changes in template files. It's nil until
+	// Watch is called.
+	watcher   config.Watcher
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // newFileWatcher creates a new file watcher instance.
-func newFileWatcher(oldLinker *linker.NodeLinker) *fileWatcher {
+func newFileWatcher(oldLinker *linker.NodeLinker, onReload func(*fail.Error)) *fileWatcher {
 	return &fileWatcher{
-		linker:    oldLinker,
-		logger:    NewWatcherLogger(),
-		files:     nil,
-		fileCount: 0,
+		linker:      oldLinker,
+		files:       nil,
+		parseErrors: map[string]*fail.Error{},
+		onReload:    onReload,
 	}
 }
 
 // Watch starts monitoring files in a background goroutine.
 // It detects file creation, deletion, and modifications, then reparses and relinks accordingly.
-func (fw *fileWatcher) Watch() {
-	if userConf.UsesFS() {
-		fw.logger.Fatal("cannot use config.FileWatcher when using config.TemplateFS")
-	}
-
-	fw.logger.Info("watching files for changes...")
+// It uses config.Watcher when it's set, file system events for config.TemplateDir
+// when the platform supports them, and polls config.TemplateFS otherwise.
+func (fw *fileWatcher) Watch() error {
+	userConf.Log().Info("watching templates for changes", "dir", userConf.TemplateDir)
 
 	var err error
 	fw.files, err = locateFiles()
 	if err != nil {
-		fw.logger.Fatal("error locating files " + err.Error())
+		return fmt.Errorf("cannot locate templates: %w", err)
+	}
+
+	fw.watcher = fw.newWatcher()
+	if err := fw.watcher.Watch(fw.handleChanges); err != nil {
+		return fmt.Errorf("cannot start file watcher: %w", err)
 	}
 
-	fw.fileCount = fw.countFiles()
-	fw.ticker = time.NewTicker(userConf.WatcherInterval)
+	return nil
+}
 
-	go func() {
-		for range fw.ticker.C {
-			fw.tick()
+// Close stops the watcher. It's safe to call it multiple times.
+func (fw *fileWatcher) Close() error {
+	fw.closeOnce.Do(func() {
+		if fw.watcher != nil {
+			fw.closeErr = fw.watcher.Close()
 		}
-	}()
-}
+	})
 
-func (fw *fileWatcher) tick() {
-	fw.detectAndHandleFileChanges()
-	fw.processModifiedFiles()
-	fw.relinkPrograms()
+	return fw.closeErr
 }
 
-func (fw *fileWatcher) detectAndHandleFileChanges() {
-	currentCount := fw.countFiles()
-	if currentCount == fw.fileCount {
-		return
+// newWatcher returns the watcher that reports changes in template files.
+func (fw *fileWatcher) newWatcher() config.Watcher {
+	if userConf.Watcher != nil {
+		return userConf.Watcher
 	}
 
-	fw.handleNewOrDeletedFiles()
-	fw.fileCount = currentCount
-}
+	if !userConf.UsesFS() {
+		w, err := newEventWatcher(userConf.TemplateDir, userConf.TemplateExt)
+		if err == nil {
+			return w
+		}
 
-func (fw *fileWatcher) processModifiedFiles() {
-	if fw.removeDeletedFiles() > 0 {
-		fw.handleNewOrDeletedFiles()
-		fw.fileCount = fw.countFiles()
-		return
+		userConf.Log().Info("file watcher falls back to polling", "reason", err)
 	}
 
-	for _, f := range fw.files {
-		fw.updateFileIfModified(f)
-	}
+	return newPollWatcher(userConf.TemplateFS, userConf.TemplateExt, userConf.WatcherInterval)
 }
 
-func (fw *fileWatcher) removeDeletedFiles() int {
-	validFiles := fw.files[:0]
-	deletedCount := 0
+// handleChanges reparses only the given changed files and relinks programs.
+// Programs that depend on a changed file through @use or @component don't
+// need to be reparsed, relinking points them to the new program.
+func (fw *fileWatcher) handleChanges(paths []string) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
 
-	for _, f := range fw.files {
-		if fw.fileWasDeleted(f) {
-			fw.handleDeletedFile(f)
-			deletedCount++
+	rescan := false
+
+	for _, path := range paths {
+		f := fw.findFileByPath(path)
+		if f == nil || fw.fileWasDeleted(f) {
+			rescan = true
 			continue
 		}
-		validFiles = append(validFiles, f)
+
+		fw.reparseFile(f)
 	}
 
-	fw.files = validFiles
-	return deletedCount
-}
+	if rescan {
+		fw.handleNewOrDeletedFiles()
+	}
 
-func (fw *fileWatcher) fileWasDeleted(f *file.SourceFile) bool {
-	_, err := os.Stat(f.Abs)
-	return os.IsNotExist(err)
+	if fw.changed {
+		fw.relinkPrograms()
+	}
 }
 
-func (fw *fileWatcher) handleDeletedFile(f *file.SourceFile) {
-	fw.logger.Info("removed " + f.Rel)
-	fw.removeProgramByName(f.Name)
+func (fw *fileWatcher) fileWasDeleted(f *file.SourceFile) bool {
+	_, err := fs.Stat(userConf.TemplateFS, f.Path)
+	return errors.Is(err, fs.ErrNotExist)
 }
 
-// handleNewOrDeletedFiles re-locates files and updates tracking when file
-// count changes.
+// handleNewOrDeletedFiles re-locates files, parses new ones and removes
+// programs of deleted ones.
 func (fw *fileWatcher) handleNewOrDeletedFiles() {
-	fw.logger.Info("file count changed, updating...")
 	oldFiles := fw.files
 
 	files, err := locateFiles()
 	if err != nil {
-		fw.logger.Fatal("error locating files " + err.Error())
+		userConf.Log().Error("cannot locate templates", "error", err)
+		return
 	}
 
 	fw.files = files
-	fw.markNewFilesForParsing(oldFiles)
-	fw.cleanupDeletedPrograms(oldFiles)
-}
 
-// updateFileIfModified reparses a file if it has been modified since last check.
-func (fw *fileWatcher) updateFileIfModified(f *file.SourceFile) {
-	modTime := fw.getFileModTime(f)
-	if modTime.IsZero() || !modTime.After(f.ModTime) {
-		return
+	oldSet := makeFileNameSet(oldFiles)
+	for _, f := range fw.files {
+		if !oldSet[f.Name] {
+			fw.reparseFile(f)
+		}
 	}
 
-	fw.logger.Info("updated " + f.Rel)
-	f.ModTime = modTime
+	for name := range fw.findDeletedFiles(oldFiles) {
+		userConf.Log().Info("template removed", "template", name)
+		fw.removeProgramByName(name)
+		fw.changed = true
+	}
+}
+
+// reparseFile parses the file again and replaces its program.
+func (fw *fileWatcher) reparseFile(f *file.SourceFile) {
+	userConf.Log().Info("template updated", "template", f.Name, "path", f.Abs)
+	fw.changed = true
+
+	if info, err := fs.Stat(userConf.TemplateFS, f.Path); err == nil {
+		f.ModTime = info.ModTime()
+	}
 
 	prog, failure, parseErr := parseFile(f)
 	if parseErr != nil {
-		fw.logger.Error(parseErr.Error())
+		userConf.Log().Error(
+			"cannot read template",
+			"template", f.Name,
+			"path", f.Abs,
+			"error", parseErr,
+		)
 		fw.removeProgramByName(f.Name)
 		return
 	}
 
 	if failure != nil {
-		fw.logger.Error(failure.Error().Error())
+		fw.parseErrors[f.Name] = failure
+		return
 	}
 
+	delete(fw.parseErrors, f.Name)
 	fw.updateOrAddProgram(prog)
 }
 
@@ -166,52 +208,69 @@ func (fw *fileWatcher) updateOrAddProgram(prog *ast.Program) {
 	})
 }
 
-// relinkPrograms links all programs together and tracks any linking errors.
+// relinkPrograms links all programs together and tracks any linking
+// or parsing errors. Parsing errors take precedence over linking errors.
 func (fw *fileWatcher) relinkPrograms() {
+	var failure *fail.Error
+
 	fw.withLock(func() {
 		ln := linker.New(fw.linker.Programs)
-		failure := ln.LinkNodes()
+		failure = ln.LinkNodes()
 		fw.linker.Programs = ln.Programs
 
+		if parseFailure := fw.firstParseError(); parseFailure != nil {
+			failure = parseFailure
+		}
+
 		fw.trackLinkingError(failure)
 	})
+
+	fw.changed = false
+
+	if fw.onReload != nil {
+		fw.onReload(failure)
+	}
+}
+
+// firstParseError returns the parse error of the first file in
+// alphabetical order, ignoring files that don't exist anymore.
+func (fw *fileWatcher) firstParseError() *fail.Error {
+	names := make([]string, 0, len(fw.parseErrors))
+	for name := range fw.parseErrors {
+		if !fw.fileExists(name) {
+			delete(fw.parseErrors, name)
+			continue
+		}
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return fw.parseErrors[slices.Min(names)]
 }
 
 // trackLinkingError logs linking errors once and stores them for Template access.
 func (fw *fileWatcher) trackLinkingError(failure *fail.Error) {
 	if failure == nil {
 		if fw.lastError != "" {
-			fw.logger.Info("all templates are valid!")
+			userConf.Log().Info("all templates are valid")
 			fw.lastError = ""
 		}
 		fw.linker.LinkError = nil
 		return
 	}
 
-	errMsg := failure.Error().Error()
+	errMsg := failure.Error()
 	if errMsg != fw.lastError {
-		fw.logger.Error(errMsg)
+		logFailure(slog.LevelError, "templates are invalid", failure)
 		fw.lastError = errMsg
 	}
 
 	fw.linker.LinkError = failure
 }
 
-// cleanupDeletedPrograms removes programs from the linker that no longer exist on disk.
-func (fw *fileWatcher) cleanupDeletedPrograms(oldFiles []*file.SourceFile) {
-	deletedFiles := fw.findDeletedFiles(oldFiles)
-
-	fw.withLock(func() {
-		newProgs := fw.linker.Programs[:0]
-		for _, prog := range fw.linker.Programs {
-			if !deletedFiles[prog.Name] {
-				newProgs = append(newProgs, prog)
-			}
-		}
-		fw.linker.Programs = newProgs
-	})
-}
-
 // findDeletedFiles returns a map of file names that existed in oldFiles but no longer
 // exist in fw.files.
 func (fw *fileWatcher) findDeletedFiles(oldFiles []*file.SourceFile) map[string]bool {
@@ -240,6 +299,17 @@ func (fw *fileWatcher) removeProgramByName(name string) {
 	})
 }
 
+// findFileByPath returns the tracked file with the given config.TemplateFS path.
+func (fw *fileWatcher) findFileByPath(path string) *file.SourceFile {
+	for _, f := range fw.files {
+		if f.Path == path {
+			return f
+		}
+	}
+
+	return nil
+}
+
 // fileExists checks if a file with the given name is in the current file list.
 func (fw *fileWatcher) fileExists(name string) bool {
 	for _, f := range fw.files {
@@ -257,48 +327,3 @@ func (fw *fileWatcher) withLock(fn func()) {
 	defer fw.linker.Unlock()
 	fn()
 }
-
-// getFileModTime retrieves the last modification time of a file.
-// Returns zero time if the file does not exist or cannot be accessed.
-func (fw *fileWatcher) getFileModTime(f *file.SourceFile) time.Time {
-	if _, err := os.Stat(f.Abs); os.IsNotExist(err) {
-		return time.Time{}
-	}
-
-	info, err := os.Stat(f.Abs)
-	if err != nil {
-		fw.logger.Error("failed to stat file " + f.Abs)
-		return time.Time{}
-	}
-
-	return info.ModTime()
-}
-
-// markNewFilesForParsing sets ModTime to zero for newly created files to force reparse.
-func (fw *fileWatcher) markNewFilesForParsing(oldFiles []*file.SourceFile) {
-	oldSet := makeFileNameSet(oldFiles)
-	for _, f := range fw.files {
-		if !oldSet[f.Name] {
-			f.ModTime = time.Time{}
-		}
-	}
-}
-
-func (fw *fileWatcher) countFiles() int {
-	count := 0
-	err := filepath.WalkDir(
-		userConf.TemplateDir,
-		func(path string, d os.DirEntry, err error) error {
-			if err == nil && !d.IsDir() && strings.HasSuffix(path, userConf.TemplateExt) {
-				count++
-			}
-			return nil
-		},
-	)
-
-	if err != nil {
-		fw.logger.Error("error couting files " + err.Error())
-	}
-
-	return count
-}
@@ -0,0 +1,244 @@
+package textwire
+
+import (
+	"encoding/binary"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/textwire/textwire/v4/config"
+)
+
+// watcherDebounce is how long the event watcher waits after the last file
+// system event before handling the collected changes. Editors usually write
+// a file in several steps, this way we reparse it only once.
+const watcherDebounce = 100 * time.Millisecond
+
+const inotifyMask = syscall.IN_CREATE |
+	syscall.IN_CLOSE_WRITE |
+	syscall.IN_MODIFY |
+	syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM |
+	syscall.IN_MOVED_TO |
+	syscall.IN_DELETE_SELF
+
+// inotifyWatcher recursively watches a directory using Linux inotify API.
+type inotifyWatcher struct {
+	// fd is kept separately because calling file.Fd() would switch
+	// the file into blocking mode and Close wouldn't interrupt Read.
+	fd   int
+	file *os.File
+
+	// root is the absolute path to the watched directory.
+	root string
+
+	// ext filters out changes of files that are not templates,
+	// like swap files created by editors.
+	ext string
+
+	// dirs maps watch descriptors to absolute directory paths.
+	dirs map[int32]string
+}
+
+// newEventWatcher returns a watcher that reports changes in the given
+// directory using inotify.
+func newEventWatcher(dir, ext string) (config.Watcher, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := newInotifyWatcher(root)
+	if err != nil {
+		return nil, err
+	}
+
+	w.ext = ext
+
+	return w, nil
+}
+
+func newInotifyWatcher(root string) (*inotifyWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &inotifyWatcher{
+		fd:   fd,
+		file: os.NewFile(uintptr(fd), "inotify"),
+		root: root,
+		dirs: map[int32]string{},
+	}
+
+	if _, err := w.addDirRecursive(root); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// addDirRecursive adds watches to the given directory and all of its
+// subdirectories. It returns the paths of the files found inside.
+func (w *inotifyWatcher) addDirRecursive(root string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+
+		wd, err := syscall.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			return err
+		}
+
+		w.dirs[int32(wd)] = path
+
+		return nil
+	})
+
+	return files, err
+}
+
+// Watch starts reading events in a background goroutine and calls onChange
+// with paths relative to the watched directory.
+func (w *inotifyWatcher) Watch(onChange func(paths []string)) error {
+	go w.run(watcherDebounce, func(absPaths []string) {
+		if paths := w.relPaths(absPaths); len(paths) > 0 {
+			onChange(paths)
+		}
+	})
+
+	return nil
+}
+
+// relPaths converts absolute paths to slash-separated paths relative to
+// the watched directory and skips files with a different extension.
+// Directories are kept because their files have to be rescanned.
+func (w *inotifyWatcher) relPaths(absPaths []string) []string {
+	paths := make([]string, 0, len(absPaths))
+
+	for _, absPath := range absPaths {
+		rel, err := filepath.Rel(w.root, absPath)
+		if err != nil {
+			continue
+		}
+
+		if !strings.HasSuffix(rel, w.ext) && filepath.Ext(rel) != "" {
+			continue
+		}
+
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+
+	return paths
+}
+
+// run reads events until the watcher is closed and calls onChange with
+// the absolute paths of changed files and directories after each burst
+// of events has settled down for the delay duration.
+func (w *inotifyWatcher) run(delay time.Duration, onChange func(absPaths []string)) {
+	events := make(chan string)
+	go w.readEvents(events)
+
+	pending := map[string]struct{}{}
+	timer := time.NewTimer(delay)
+	timer.Stop()
+
+	for {
+		select {
+		case path, ok := <-events:
+			if !ok {
+				timer.Stop()
+				return
+			}
+
+			pending[path] = struct{}{}
+			timer.Reset(delay)
+		case <-timer.C:
+			paths := slices.Sorted(maps.Keys(pending))
+			clear(pending)
+			onChange(paths)
+		}
+	}
+}
+
+// readEvents decodes raw inotify events and sends affected paths to
+// the events channel. The channel is closed when the watcher is closed.
+func (w *inotifyWatcher) readEvents(events chan<- string) {
+	defer close(events)
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			wd := int32(binary.NativeEndian.Uint32(buf[offset:]))
+			mask := binary.NativeEndian.Uint32(buf[offset+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+
+			nameStart := offset + syscall.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[nameStart:nameStart+nameLen]), "\x00")
+			offset = nameStart + nameLen
+
+			for _, path := range w.handleEvent(wd, mask, name) {
+				events <- path
+			}
+		}
+	}
+}
+
+// handleEvent updates watched directories and returns paths affected
+// by the event.
+func (w *inotifyWatcher) handleEvent(wd int32, mask uint32, name string) []string {
+	dir, ok := w.dirs[wd]
+	if !ok {
+		return nil
+	}
+
+	if mask&syscall.IN_IGNORED != 0 {
+		delete(w.dirs, wd)
+		return nil
+	}
+
+	if mask&syscall.IN_DELETE_SELF != 0 {
+		return []string{dir}
+	}
+
+	path := filepath.Join(dir, name)
+	isNewDir := mask&syscall.IN_ISDIR != 0 && mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0
+
+	if !isNewDir {
+		return []string{path}
+	}
+
+	// Files could be created in a new directory before we started
+	// watching it, that's why we report them as changed as well.
+	files, err := w.addDirRecursive(path)
+	if err != nil {
+		return []string{path}
+	}
+
+	return append(files, path)
+}
+
+// Close stops watching and releases the inotify file descriptor.
+func (w *inotifyWatcher) Close() error {
+	return w.file.Close()
+}
@@ -0,0 +1,82 @@
+package textwire
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestInotifyWatcher(t *testing.T) {
+	root := t.TempDir()
+
+	w, err := newInotifyWatcher(root)
+	if err != nil {
+		t.Skipf("inotify is not available: %s", err)
+	}
+
+	changes := make(chan []string, 10)
+	go w.run(10*time.Millisecond, func(absPaths []string) {
+		changes <- absPaths
+	})
+
+	defer func() {
+		if err := w.Close(); err != nil {
+			t.Errorf("unexpected error closing watcher: %s", err)
+		}
+	}()
+
+	waitFor := func(path string) {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+
+		for {
+			select {
+			case paths := <-changes:
+				if slices.Contains(paths, path) {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("expected change for %s, got none", path)
+			}
+		}
+	}
+
+	t.Run("new file", func(t *testing.T) {
+		path := filepath.Join(root, "index.tw")
+		if err := os.WriteFile(path, []byte("<h1>Hello</h1>"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		waitFor(path)
+	})
+
+	t.Run("modified file", func(t *testing.T) {
+		path := filepath.Join(root, "index.tw")
+		if err := os.WriteFile(path, []byte("<h1>Hi</h1>"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		waitFor(path)
+	})
+
+	t.Run("file in new directory", func(t *testing.T) {
+		dir := filepath.Join(root, "components")
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(dir, "book.tw")
+		if err := os.WriteFile(path, []byte("<b>book</b>"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		waitFor(path)
+	})
+
+	t.Run("deleted file", func(t *testing.T) {
+		path := filepath.Join(root, "index.tw")
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		waitFor(path)
+	})
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package textwire
+
+import (
+	"errors"
+
+	"github.com/textwire/textwire/v4/config"
+)
+
+// newEventWatcher returns an error because file system events are only
+// supported on Linux, the watcher falls back to polling on other platforms.
+func newEventWatcher(string, string) (config.Watcher, error) {
+	return nil, errors.New("file system events are only supported on Linux")
+}
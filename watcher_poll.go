@@ -0,0 +1,122 @@
+package textwire
+
+import (
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pollWatcher detects changes by walking an fs.FS every interval and
+// comparing modification times returned by fs.Stat. It works with any
+// file system, including os.DirFS and custom fs.FS implementations.
+type pollWatcher struct {
+	fsys     fs.FS
+	ext      string
+	interval time.Duration
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// modTimes maps file paths to their last known modification time.
+	modTimes map[string]time.Time
+}
+
+func newPollWatcher(fsys fs.FS, ext string, interval time.Duration) *pollWatcher {
+	return &pollWatcher{
+		fsys:     fsys,
+		ext:      ext,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+}
+
+// Watch takes the initial snapshot of files and starts polling
+// in a background goroutine.
+func (w *pollWatcher) Watch(onChange func(paths []string)) error {
+	modTimes, err := w.scan()
+	if err != nil {
+		return err
+	}
+
+	w.modTimes = modTimes
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				if paths := w.poll(); len(paths) > 0 {
+					onChange(paths)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// poll takes a new snapshot of files and returns sorted paths of files
+// that were created, modified or deleted since the previous one.
+func (w *pollWatcher) poll() []string {
+	modTimes, err := w.scan()
+	if err != nil {
+		return nil
+	}
+
+	changed := map[string]struct{}{}
+
+	for path, modTime := range modTimes {
+		oldModTime, ok := w.modTimes[path]
+		if !ok || !modTime.Equal(oldModTime) {
+			changed[path] = struct{}{}
+		}
+	}
+
+	for path := range w.modTimes {
+		if _, ok := modTimes[path]; !ok {
+			changed[path] = struct{}{}
+		}
+	}
+
+	w.modTimes = modTimes
+
+	return slices.Sorted(maps.Keys(changed))
+}
+
+// scan returns modification times of all template files in the file system.
+func (w *pollWatcher) scan() (map[string]time.Time, error) {
+	modTimes := map[string]time.Time{}
+
+	err := fs.WalkDir(w.fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(path, w.ext) {
+			return nil
+		}
+
+		info, err := fs.Stat(w.fsys, path)
+		if err != nil {
+			return err
+		}
+
+		modTimes[path] = info.ModTime()
+
+		return nil
+	})
+
+	return modTimes, err
+}
+
+// Close stops polling.
+func (w *pollWatcher) Close() error {
+	w.stopOnce.Do(func() { close(w.done) })
+	return nil
+}
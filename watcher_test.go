@@ -0,0 +1,175 @@
+package textwire
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/textwire/textwire/v4/config"
+	"github.com/textwire/textwire/v4/pkg/fail"
+)
+
+// fakeWatcher lets tests report changes manually.
+type fakeWatcher struct {
+	onChange func(paths []string)
+	err      error
+	closed   int
+}
+
+func (w *fakeWatcher) Watch(onChange func(paths []string)) error {
+	w.onChange = onChange
+	return w.err
+}
+
+func (w *fakeWatcher) Close() error {
+	w.closed++
+	return nil
+}
+
+func TestPollWatcher(t *testing.T) {
+	modTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"index.tw":            {Data: []byte("<h1>Hi</h1>"), ModTime: modTime},
+		"components/book.tw":  {Data: []byte("<b>book</b>"), ModTime: modTime},
+		"components/notes.md": {Data: []byte("# Notes"), ModTime: modTime},
+	}
+
+	w := newPollWatcher(fsys, ".tw", time.Hour)
+	if err := w.Watch(func([]string) {}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer w.Close()
+
+	if paths := w.poll(); len(paths) != 0 {
+		t.Fatalf("expected no changes, got %v", paths)
+	}
+
+	fsys["index.tw"].ModTime = modTime.Add(time.Second)
+	fsys["components/notes.md"].ModTime = modTime.Add(time.Second)
+	fsys["layouts/main.tw"] = &fstest.MapFile{Data: []byte("@reserve('content')")}
+	delete(fsys, "components/book.tw")
+
+	expect := []string{"components/book.tw", "index.tw", "layouts/main.tw"}
+	if paths := w.poll(); !slices.Equal(paths, expect) {
+		t.Fatalf("expected changes %v, got %v", expect, paths)
+	}
+
+	if paths := w.poll(); len(paths) != 0 {
+		t.Fatalf("expected no changes after poll, got %v", paths)
+	}
+}
+
+func TestFileWatcherWithTemplateFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/index.tw":            {Data: []byte("<h1>@component('~title')@end</h1>")},
+		"templates/components/title.tw": {Data: []byte("Hello")},
+	}
+
+	watcher := &fakeWatcher{}
+	tpl, failure := NewTemplate(&config.Config{
+		TemplateDir: "templates",
+		TemplateFS:  fsys,
+		FileWatcher: true,
+		Watcher:     watcher,
+	})
+	if failure != nil {
+		t.Fatalf("unexpected error: %s", failure)
+	}
+
+	defer Configure(&config.Config{})
+
+	render := func() string {
+		t.Helper()
+		out, failure := tpl.String("index", nil)
+		if failure != nil {
+			t.Fatalf("unexpected error: %s", failure)
+		}
+		return out
+	}
+
+	if out := render(); out != "<h1>Hello</h1>" {
+		t.Fatalf("expected initial output, got %q", out)
+	}
+
+	fsys["templates/components/title.tw"].Data = []byte("Bye")
+	watcher.onChange([]string{"templates/components/title.tw"})
+
+	if out := render(); out != "<h1>Bye</h1>" {
+		t.Fatalf("expected updated component, got %q", out)
+	}
+
+	delete(fsys, "templates/components/title.tw")
+	fsys["templates/components/name.tw"] = &fstest.MapFile{Data: []byte("New")}
+	fsys["templates/index.tw"].Data = []byte("<h1>@component('~name')@end</h1>")
+	watcher.onChange([]string{
+		"templates/components/name.tw",
+		"templates/components/title.tw",
+		"templates/index.tw",
+	})
+
+	if out := render(); out != "<h1>New</h1>" {
+		t.Fatalf("expected output with new component, got %q", out)
+	}
+}
+
+func TestFileWatcherClose(t *testing.T) {
+	fsys := fstest.MapFS{"templates/index.tw": {Data: []byte("<h1>Hi</h1>")}}
+	newTpl := func(w *fakeWatcher) (*Template, *fail.Error) {
+		return NewTemplate(&config.Config{
+			TemplateDir: "templates",
+			TemplateFS:  fsys,
+			FileWatcher: true,
+			Watcher:     w,
+		})
+	}
+
+	defer Configure(&config.Config{})
+
+	t.Run("closes on Close", func(t *testing.T) {
+		watcher := &fakeWatcher{}
+		tpl, failure := newTpl(watcher)
+		if failure != nil {
+			t.Fatalf("unexpected error: %s", failure)
+		}
+
+		for range 2 {
+			if err := tpl.Close(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+
+		if watcher.closed != 1 {
+			t.Fatalf("expected watcher to be closed once, got %d", watcher.closed)
+		}
+	})
+
+	t.Run("closes on reconfigure", func(t *testing.T) {
+		first, second := &fakeWatcher{}, &fakeWatcher{}
+		if _, failure := newTpl(first); failure != nil {
+			t.Fatalf("unexpected error: %s", failure)
+		}
+
+		if _, failure := newTpl(second); failure != nil {
+			t.Fatalf("unexpected error: %s", failure)
+		}
+
+		if first.closed != 1 || second.closed != 0 {
+			t.Fatalf("expected only the first watcher to be closed, got %d and %d",
+				first.closed, second.closed)
+		}
+	})
+
+	t.Run("returns watch error", func(t *testing.T) {
+		watcher := &fakeWatcher{err: errors.New("too many open files")}
+		_, failure := newTpl(watcher)
+		if failure == nil || !errors.Is(failure, watcher.err) {
+			t.Fatalf("expected watch error, got %v", failure)
+		}
+
+		if watcher.closed != 1 {
+			t.Fatalf("expected failed watcher to be closed, got %d", watcher.closed)
+		}
+	})
+}